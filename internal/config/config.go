@@ -1,6 +1,9 @@
 package config
 
 import (
+	"fmt"
+	"net/http"
+	"strings"
 	"time"
 )
 
@@ -13,21 +16,154 @@ type Config struct {
 	Endpoints      Endpoints
 }
 
-// Endpoints holds all API endpoint paths
+// Route describes an API endpoint.
+type Route struct {
+	// Operation is a stable name for the endpoint, e.g. "groups.get", suitable for logs, metrics
+	// and traces.
+	Operation string
+	Method    string
+	// Path is relative to the base URL, with {name} placeholders for path parameters.
+	Path string
+	// Idempotent routes leave the same state behind however many times they are sent.
+	Idempotent bool
+	// Retryable routes may be sent again after a network error, 429 or 5xx response.
+	Retryable bool
+	// Unversioned routes are served from the API root even when the base URL ends in /v1.
+	Unversioned bool
+	// Public routes are called without the app key and secret.
+	Public bool
+}
+
+// Segments returns the route's path segments with the placeholders replaced by params, in order.
+func (r Route) Segments(params ...string) ([]string, error) {
+	segments := strings.Split(strings.Trim(r.Path, "/"), "/")
+
+	n := 0
+	for i, segment := range segments {
+		if !strings.HasPrefix(segment, "{") || !strings.HasSuffix(segment, "}") {
+			continue
+		}
+		if n >= len(params) {
+			return nil, fmt.Errorf("%s: missing path parameter %s", r.Operation, segment)
+		}
+		segments[i] = params[n]
+		n++
+	}
+	if n != len(params) {
+		return nil, fmt.Errorf("%s: expected %d path parameters, got %d", r.Operation, n, len(params))
+	}
+
+	return segments, nil
+}
+
+// Endpoints is the table of API routes used by the client.
 type Endpoints struct {
-	Auth  AuthEndpoints
-	Users UsersEndpoints
+	AppConfig    Route
+	JWKS         Route
+	MagicLinks   MagicLinksEndpoints
+	Users        UsersEndpoints
+	UserFields   UserFieldsEndpoints
+	Groups       CRUDEndpoints
+	GroupMembers CRUDEndpoints
+	GroupInvites CRUDEndpoints
 }
 
-type AuthEndpoints struct {
-	Validate string
-	Token    string
+type MagicLinksEndpoints struct {
+	Create     Route
+	SmartLinks Route
 }
 
 type UsersEndpoints struct {
-	Get    string
-	Update string
-	Delete string
+	Get            Route
+	List           Route
+	CreateOrUpdate Route
+	Patch          Route
+	Delete         Route
+}
+
+type UserFieldsEndpoints struct {
+	Get    Route
+	Update Route
+}
+
+// CRUDEndpoints are the routes of a resource with the usual get, list, create, update and delete
+// operations.
+type CRUDEndpoints struct {
+	Get    Route
+	List   Route
+	Create Route
+	Update Route
+	Delete Route
+}
+
+func (e CRUDEndpoints) all() []Route {
+	return []Route{e.Get, e.List, e.Create, e.Update, e.Delete}
+}
+
+// All returns every route in the table.
+func (e Endpoints) All() []Route {
+	routes := []Route{
+		e.AppConfig,
+		e.JWKS,
+		e.MagicLinks.Create,
+		e.MagicLinks.SmartLinks,
+		e.Users.Get,
+		e.Users.List,
+		e.Users.CreateOrUpdate,
+		e.Users.Patch,
+		e.Users.Delete,
+		e.UserFields.Get,
+		e.UserFields.Update,
+	}
+	routes = append(routes, e.Groups.all()...)
+	routes = append(routes, e.GroupMembers.all()...)
+	routes = append(routes, e.GroupInvites.all()...)
+	return routes
+}
+
+// crud builds the routes of a resource below collection.
+func crud(name, collection, item string) CRUDEndpoints {
+	return CRUDEndpoints{
+		Get:    Route{Operation: name + ".get", Method: http.MethodGet, Path: item, Idempotent: true, Retryable: true},
+		List:   Route{Operation: name + ".list", Method: http.MethodGet, Path: collection, Idempotent: true, Retryable: true},
+		Create: Route{Operation: name + ".create", Method: http.MethodPost, Path: collection},
+		Update: Route{Operation: name + ".update", Method: http.MethodPut, Path: item, Idempotent: true, Retryable: true},
+		// a retried delete that already succeeded fails with 404, so deletes are not retried
+		Delete: Route{Operation: name + ".delete", Method: http.MethodDelete, Path: item, Idempotent: true},
+	}
+}
+
+// NewEndpoints returns the route table of the Rownd API.
+func NewEndpoints() Endpoints {
+	const (
+		user   = "/applications/{app_id}/users/{user_id}/data"
+		field  = "/applications/{app_id}/users/{user_id}/data/fields/{field}"
+		groups = "/applications/{app_id}/groups"
+		group  = groups + "/{group_id}"
+	)
+
+	return Endpoints{
+		AppConfig: Route{Operation: "app_config.get", Method: http.MethodGet, Path: "/hub/app-config", Idempotent: true, Retryable: true},
+		JWKS:      Route{Operation: "jwks.get", Method: http.MethodGet, Path: "/hub/auth/keys", Idempotent: true, Retryable: true, Unversioned: true, Public: true},
+		MagicLinks: MagicLinksEndpoints{
+			Create:     Route{Operation: "magic_links.create", Method: http.MethodPost, Path: "/hub/auth/magic"},
+			SmartLinks: Route{Operation: "smart_links.create", Method: http.MethodPost, Path: "/hub/smart-links", Public: true},
+		},
+		Users: UsersEndpoints{
+			Get:            Route{Operation: "users.get", Method: http.MethodGet, Path: user, Idempotent: true, Retryable: true},
+			List:           Route{Operation: "users.list", Method: http.MethodGet, Path: "/applications/{app_id}/users/data", Idempotent: true, Retryable: true},
+			CreateOrUpdate: Route{Operation: "users.create_or_update", Method: http.MethodPut, Path: user, Idempotent: true, Retryable: true},
+			Patch:          Route{Operation: "users.patch", Method: http.MethodPatch, Path: user, Idempotent: true, Retryable: true},
+			Delete:         Route{Operation: "users.delete", Method: http.MethodDelete, Path: user, Idempotent: true},
+		},
+		UserFields: UserFieldsEndpoints{
+			Get:    Route{Operation: "user_fields.get", Method: http.MethodGet, Path: field, Idempotent: true, Retryable: true},
+			Update: Route{Operation: "user_fields.update", Method: http.MethodPut, Path: field, Idempotent: true, Retryable: true},
+		},
+		Groups:       crud("groups", groups, group),
+		GroupMembers: crud("group_members", group+"/members", group+"/members/{member_id}"),
+		GroupInvites: crud("group_invites", group+"/invites", group+"/invites/{invite_id}"),
+	}
 }
 
 // NewConfig returns a new configuration with default values
@@ -37,16 +173,6 @@ func NewConfig() *Config {
 		DefaultTimeout: 30 * time.Second,
 		MaxRetries:     3,
 		UserAgent:      "rownd-go-sdk/1.0",
-		Endpoints: Endpoints{
-			Auth: AuthEndpoints{
-				Validate: "/hub/auth/validate",
-				Token:    "/hub/auth/token",
-			},
-			Users: UsersEndpoints{
-				Get:    "/hub/users/%s",
-				Update: "/hub/users/%s",
-				Delete: "/hub/users/%s",
-			},
-		},
+		Endpoints:      NewEndpoints(),
 	}
 }
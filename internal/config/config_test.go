@@ -0,0 +1,36 @@
+package config_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/rownd/client-go/internal/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEndpoints(t *testing.T) {
+	endpoints := config.NewEndpoints()
+
+	operations := map[string]bool{}
+	for _, route := range endpoints.All() {
+		assert.NotEmpty(t, route.Operation)
+		assert.False(t, operations[route.Operation], "duplicate operation %s", route.Operation)
+		operations[route.Operation] = true
+
+		assert.NotEmpty(t, route.Method, route.Operation)
+		assert.False(t, route.Retryable && !route.Idempotent, "%s is retryable but not idempotent", route.Operation)
+		if route.Method == http.MethodPost {
+			assert.False(t, route.Idempotent, route.Operation)
+		}
+	}
+
+	segments, err := endpoints.GroupMembers.Get.Segments("app_1", "group_1", "member_1")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"applications", "app_1", "groups", "group_1", "members", "member_1"}, segments)
+
+	_, err = endpoints.GroupMembers.Get.Segments("app_1", "group_1")
+	assert.ErrorContains(t, err, "group_members.get: missing path parameter {member_id}")
+
+	_, err = endpoints.AppConfig.Segments("extra")
+	assert.ErrorContains(t, err, "expected 0 path parameters, got 1")
+}
@@ -2,15 +2,24 @@ package rownd
 
 import (
 	"context"
-	"net/http"
 )
 
 type AppConfig struct {
 	App struct {
-		Id string `json:"id"`
+		Id     string                    `json:"id"`
+		Schema map[string]AppSchemaField `json:"schema"`
 	}
 }
 
+// AppSchemaField describes a user profile field configured for the app.
+type AppSchemaField struct {
+	DisplayName  string `json:"display_name"`
+	Type         string `json:"type"`
+	DataCategory string `json:"data_category"`
+	Required     bool   `json:"required"`
+	OwnedBy      string `json:"owned_by"`
+}
+
 type appConfigClient struct {
 	*Client
 }
@@ -22,16 +31,17 @@ func (c *appConfigClient) LoadAppConfig(ctx context.Context) {
 	}
 
 	c.appID = config.App.Id
+	c.appSchema = config.App.Schema
 }
 
 func (c *appConfigClient) FetchAppConfig(ctx context.Context) (*AppConfig, error) {
-	endpoint, err := c.rowndURL("hub", "app-config")
+	endpoint, err := c.endpoint(c.endpoints.AppConfig)
 	if err != nil {
 		return nil, err
 	}
 
 	var response *AppConfig
-	if err := c.request(ctx, http.MethodGet, endpoint.String(), nil, &response, c.httpClientOpts...); err != nil {
+	if err := c.request(ctx, endpoint, nil, &response); err != nil {
 		return nil, err
 	}
 
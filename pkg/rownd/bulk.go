@@ -0,0 +1,295 @@
+package rownd
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/rownd/client-go/internal/config"
+)
+
+const (
+	defaultBulkConcurrency int           = 4
+	defaultBulkMaxAttempts int           = 3
+	defaultBulkRetryDelay  time.Duration = 500 * time.Millisecond
+)
+
+// BulkOptions configures a bulk user operation.
+type BulkOptions struct {
+	// Concurrency is the number of requests in flight at once. Defaults to 4.
+	Concurrency int
+
+	// MaxAttempts is the number of times an item is tried when it fails with a retryable error
+	// (network errors, 429 and 5xx responses). Defaults to 3. Only requests that are safe to send
+	// again are retried, so deletes and upserts with a generated user ID are tried once. When the
+	// client was created WithRetries, retries are left to it and every item is tried once.
+	MaxAttempts int
+
+	// RetryDelay is multiplied by the attempt number to compute the delay before a retry.
+	// Defaults to 500ms.
+	RetryDelay time.Duration
+
+	// MaxFailures stops dispatching new items once this many items have failed. Zero means the
+	// operation never stops early.
+	MaxFailures int
+
+	// Skip is the number of leading items to skip, typically the last value passed to
+	// OnCheckpoint by a previous run.
+	Skip int
+
+	// OnCheckpoint is called with the number of leading items (including skipped ones) that have
+	// all finished, successfully or not. Persist it and pass it as Skip to resume.
+	OnCheckpoint func(completed int)
+}
+
+func (o BulkOptions) validate() error {
+	var errs []error
+
+	if o.Concurrency < 0 {
+		errs = append(errs, NewError(ErrValidation, "concurrency must not be negative", nil))
+	}
+	if o.MaxAttempts < 0 {
+		errs = append(errs, NewError(ErrValidation, "max attempts must not be negative", nil))
+	}
+	if o.MaxFailures < 0 {
+		errs = append(errs, NewError(ErrValidation, "max failures must not be negative", nil))
+	}
+	if o.Skip < 0 {
+		errs = append(errs, NewError(ErrValidation, "skip must not be negative", nil))
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+
+	return &MultiError{errors: errs}
+}
+
+func (o BulkOptions) withDefaults() BulkOptions {
+	if o.Concurrency == 0 {
+		o.Concurrency = defaultBulkConcurrency
+	}
+	if o.MaxAttempts == 0 {
+		o.MaxAttempts = defaultBulkMaxAttempts
+	}
+	if o.RetryDelay == 0 {
+		o.RetryDelay = defaultBulkRetryDelay
+	}
+	return o
+}
+
+// BulkItemResult is the outcome of a single item in a bulk operation.
+type BulkItemResult struct {
+	// Index is the position of the item in the input, including skipped items.
+	Index int
+	// UserID is the ID of the affected user, when known.
+	UserID string
+	// User is the API response for patch and upsert operations.
+	User *User
+	// Err is the error of the last attempt, or nil on success.
+	Err error
+	// ErrKind classifies Err.
+	ErrKind ErrKind
+	// Attempts is the number of times the item was tried. Retries made by the client's
+	// WithRetries policy are not counted.
+	Attempts int
+}
+
+// BulkResult summarises a bulk operation. Results are ordered by Index.
+type BulkResult struct {
+	Results   []BulkItemResult
+	Succeeded int
+	Failed    int
+	// Aborted is set when MaxFailures was reached and remaining items were not attempted.
+	Aborted bool
+}
+
+// BulkPatch patches many users. See BulkPatchSeq.
+func (c *userClient) BulkPatch(ctx context.Context, requests []PatchUserRequest, opts BulkOptions) (*BulkResult, error) {
+	return c.BulkPatchSeq(ctx, sliceSeq(requests), opts)
+}
+
+// BulkPatchSeq patches users produced by an iterator, such as an iter.Seq in Go 1.23+.
+func (c *userClient) BulkPatchSeq(ctx context.Context, requests func(yield func(PatchUserRequest) bool), opts BulkOptions) (*BulkResult, error) {
+	retryable := func(PatchUserRequest) bool {
+		return c.retriesBulkItems(c.endpoints.Users.Patch)
+	}
+	return runBulk(ctx, requests, opts, retryable, func(ctx context.Context, request PatchUserRequest) (string, *User, error) {
+		user, err := c.Patch(ctx, request)
+		return request.UserID, user, err
+	})
+}
+
+// BulkUpsert creates or updates many users. See BulkUpsertSeq.
+func (c *userClient) BulkUpsert(ctx context.Context, requests []CreateOrUpdateUserRequest, opts BulkOptions) (*BulkResult, error) {
+	return c.BulkUpsertSeq(ctx, sliceSeq(requests), opts)
+}
+
+// BulkUpsertSeq creates or updates users produced by an iterator, such as an iter.Seq in Go 1.23+.
+func (c *userClient) BulkUpsertSeq(ctx context.Context, requests func(yield func(CreateOrUpdateUserRequest) bool), opts BulkOptions) (*BulkResult, error) {
+	// sending a generated user ID again creates another user
+	retryable := func(request CreateOrUpdateUserRequest) bool {
+		return !isGeneratedUserID(request.UserID) && c.retriesBulkItems(c.endpoints.Users.CreateOrUpdate)
+	}
+	return runBulk(ctx, requests, opts, retryable, func(ctx context.Context, request CreateOrUpdateUserRequest) (string, *User, error) {
+		user, err := c.CreateOrUpdate(ctx, request)
+		if err != nil {
+			return request.UserID, nil, err
+		}
+		return user.GetID(), user, nil
+	})
+}
+
+// BulkDelete deletes many users. See BulkDeleteSeq.
+func (c *userClient) BulkDelete(ctx context.Context, requests []DeleteUserRequest, opts BulkOptions) (*BulkResult, error) {
+	return c.BulkDeleteSeq(ctx, sliceSeq(requests), opts)
+}
+
+// BulkDeleteSeq deletes users produced by an iterator, such as an iter.Seq in Go 1.23+.
+func (c *userClient) BulkDeleteSeq(ctx context.Context, requests func(yield func(DeleteUserRequest) bool), opts BulkOptions) (*BulkResult, error) {
+	retryable := func(DeleteUserRequest) bool {
+		return c.retriesBulkItems(c.endpoints.Users.Delete)
+	}
+	return runBulk(ctx, requests, opts, retryable, func(ctx context.Context, request DeleteUserRequest) (string, *User, error) {
+		return request.UserID, nil, c.Delete(ctx, request)
+	})
+}
+
+// retriesBulkItems reports whether bulk items sent to the route are retried by runBulk. Routes
+// that aren't retryable are tried once, and so are all routes when the client retries them itself.
+func (c *userClient) retriesBulkItems(route config.Route) bool {
+	return route.Retryable && c.maxAttempts <= 1
+}
+
+func sliceSeq[T any](items []T) func(yield func(T) bool) {
+	return func(yield func(T) bool) {
+		for _, item := range items {
+			if !yield(item) {
+				return
+			}
+		}
+	}
+}
+
+type bulkJob[T any] struct {
+	index int
+	item  T
+}
+
+// runBulk dispatches items to a pool of workers and collects per-item results. Items are retried
+// only when retryable returns true for them. Only context cancellation and invalid options are
+// reported as errors; item failures are part of the result.
+func runBulk[T any](ctx context.Context, items func(yield func(T) bool), opts BulkOptions, retryable func(T) bool, do func(context.Context, T) (string, *User, error)) (*BulkResult, error) {
+	if err := opts.validate(); err != nil {
+		return nil, err
+	}
+	opts = opts.withDefaults()
+
+	var (
+		mu       sync.Mutex
+		result   = &BulkResult{}
+		done     = map[int]bool{}
+		next     = opts.Skip
+		aborted  = make(chan struct{})
+		abortOne sync.Once
+		jobs     = make(chan bulkJob[T])
+		wg       sync.WaitGroup
+	)
+
+	record := func(r BulkItemResult) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		result.Results = append(result.Results, r)
+		if r.Err == nil {
+			result.Succeeded++
+		} else {
+			result.Failed++
+			if opts.MaxFailures > 0 && result.Failed >= opts.MaxFailures {
+				result.Aborted = true
+				abortOne.Do(func() { close(aborted) })
+			}
+		}
+
+		done[r.Index] = true
+		advanced := false
+		for done[next] {
+			delete(done, next)
+			next++
+			advanced = true
+		}
+		if advanced && opts.OnCheckpoint != nil {
+			opts.OnCheckpoint(next)
+		}
+	}
+
+	for i := 0; i < opts.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range jobs {
+				record(runBulkItem(ctx, job, opts, retryable(job.item), do))
+			}
+		}()
+	}
+
+	index := 0
+	items(func(item T) bool {
+		defer func() { index++ }()
+		if index < opts.Skip {
+			return true
+		}
+
+		select {
+		case <-ctx.Done():
+			return false
+		case <-aborted:
+			return false
+		case jobs <- bulkJob[T]{index: index, item: item}:
+			return true
+		}
+	})
+	close(jobs)
+	wg.Wait()
+
+	sort.Slice(result.Results, func(i, j int) bool {
+		return result.Results[i].Index < result.Results[j].Index
+	})
+
+	if err := ctx.Err(); err != nil {
+		return result, err
+	}
+
+	return result, nil
+}
+
+func runBulkItem[T any](ctx context.Context, job bulkJob[T], opts BulkOptions, retryable bool, do func(context.Context, T) (string, *User, error)) BulkItemResult {
+	r := BulkItemResult{Index: job.index}
+
+	maxAttempts := opts.MaxAttempts
+	if !retryable {
+		maxAttempts = 1
+	}
+	for r.Attempts < maxAttempts {
+		if r.Attempts > 0 {
+			timer := time.NewTimer(time.Duration(r.Attempts) * opts.RetryDelay)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				r.ErrKind = KindOf(r.Err)
+				return r
+			case <-timer.C:
+			}
+		}
+
+		r.Attempts++
+		r.UserID, r.User, r.Err = do(ctx, job.item)
+		if r.Err == nil || ctx.Err() != nil || !isRetryable(r.Err) {
+			break
+		}
+	}
+
+	r.ErrKind = KindOf(r.Err)
+	return r
+}
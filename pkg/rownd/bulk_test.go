@@ -0,0 +1,138 @@
+package rownd_test
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/rownd/client-go/pkg/rownd"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBulkUserOperations(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		attempts = map[string]int{}
+		deleted  atomic.Int32
+	)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/applications/", func(w http.ResponseWriter, r *http.Request) {
+		userID := pathSegments(r)[3]
+		if r.Method == http.MethodDelete {
+			if strings.HasPrefix(userID, "flaky") {
+				writeJSON(w, http.StatusServiceUnavailable, map[string]any{"error": "unavailable"})
+				return
+			}
+			deleted.Add(1)
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		mu.Lock()
+		attempts[userID]++
+		n := attempts[userID]
+		mu.Unlock()
+
+		switch {
+		case (strings.HasPrefix(userID, "flaky") || userID == rownd.UserIDUUID) && n == 1:
+			writeJSON(w, http.StatusServiceUnavailable, map[string]any{"error": "unavailable"})
+		case strings.HasPrefix(userID, "garbled"):
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte("{not json"))
+		case strings.HasPrefix(userID, "missing"):
+			writeJSON(w, http.StatusNotFound, map[string]any{"error": "not found"})
+		default:
+			writeJSON(w, http.StatusOK, map[string]any{"data": map[string]any{"user_id": userID}})
+		}
+	})
+
+	client := newTestClient(t, mux)
+	ctx := context.Background()
+
+	t.Run("patch with retries and failures", func(t *testing.T) {
+		var checkpoints []int
+		result, err := client.Users.BulkPatch(ctx, []rownd.PatchUserRequest{
+			{UserID: "user_1", Data: map[string]any{"a": 1}},
+			{UserID: "flaky_1", Data: map[string]any{"a": 1}},
+			{UserID: "missing_1", Data: map[string]any{"a": 1}},
+			{UserID: ""},
+		}, rownd.BulkOptions{
+			Concurrency:  2,
+			RetryDelay:   1,
+			OnCheckpoint: func(completed int) { checkpoints = append(checkpoints, completed) },
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 2, result.Succeeded)
+		assert.Equal(t, 2, result.Failed)
+		assert.Len(t, result.Results, 4)
+
+		assert.Equal(t, 2, result.Results[1].Attempts)
+		assert.NoError(t, result.Results[1].Err)
+		assert.Equal(t, 1, result.Results[2].Attempts)
+		assert.Equal(t, rownd.ErrNotFound, result.Results[2].ErrKind)
+		assert.Equal(t, rownd.ErrValidation, result.Results[3].ErrKind)
+		assert.Equal(t, 4, checkpoints[len(checkpoints)-1])
+	})
+
+	t.Run("writes that landed are not retried", func(t *testing.T) {
+		result, err := client.Users.BulkPatch(ctx, []rownd.PatchUserRequest{
+			{UserID: "garbled_1", Data: map[string]any{"a": 1}},
+		}, rownd.BulkOptions{RetryDelay: 1})
+		assert.NoError(t, err)
+		assert.Equal(t, 1, result.Results[0].Attempts)
+		assert.Equal(t, rownd.ErrInternal, result.Results[0].ErrKind)
+	})
+
+	t.Run("unsafe requests are tried once", func(t *testing.T) {
+		result, err := client.Users.BulkUpsert(ctx, []rownd.CreateOrUpdateUserRequest{
+			{UserID: rownd.UserIDUUID, Data: map[string]any{"email": "new@example.com"}},
+		}, rownd.BulkOptions{RetryDelay: 1})
+		assert.NoError(t, err)
+		assert.Equal(t, 1, result.Results[0].Attempts, "a retry would create a second user")
+		assert.Equal(t, rownd.ErrAPI, result.Results[0].ErrKind)
+
+		result, err = client.Users.BulkDelete(ctx, []rownd.DeleteUserRequest{{UserID: "flaky_delete"}}, rownd.BulkOptions{RetryDelay: 1})
+		assert.NoError(t, err)
+		assert.Equal(t, 1, result.Results[0].Attempts)
+	})
+
+	t.Run("client retries are not multiplied", func(t *testing.T) {
+		retrying := newTestClient(t, mux, rownd.WithRetries(3, 1))
+		result, err := retrying.Users.BulkPatch(ctx, []rownd.PatchUserRequest{
+			{UserID: "flaky_2", Data: map[string]any{"a": 1}},
+		}, rownd.BulkOptions{RetryDelay: 1})
+		assert.NoError(t, err)
+		assert.NoError(t, result.Results[0].Err)
+		assert.Equal(t, 1, result.Results[0].Attempts)
+
+		mu.Lock()
+		defer mu.Unlock()
+		assert.Equal(t, 2, attempts["flaky_2"], "the client retried once and the bulk item was not retried")
+	})
+
+	t.Run("stops at failure threshold", func(t *testing.T) {
+		requests := make([]rownd.PatchUserRequest, 20)
+		for i := range requests {
+			requests[i] = rownd.PatchUserRequest{UserID: "missing_" + string(rune('a'+i))}
+		}
+
+		result, err := client.Users.BulkPatch(ctx, requests, rownd.BulkOptions{Concurrency: 1, MaxFailures: 3})
+		assert.NoError(t, err)
+		assert.True(t, result.Aborted)
+		assert.Less(t, len(result.Results), len(requests))
+	})
+
+	t.Run("delete resumes after skip", func(t *testing.T) {
+		result, err := client.Users.BulkDelete(ctx, []rownd.DeleteUserRequest{
+			{UserID: "user_1"}, {UserID: "user_2"}, {UserID: "user_3"},
+		}, rownd.BulkOptions{Skip: 1})
+		assert.NoError(t, err)
+		assert.Equal(t, 2, result.Succeeded)
+		assert.Equal(t, 1, result.Results[0].Index)
+		assert.Equal(t, int32(2), deleted.Load())
+	})
+}
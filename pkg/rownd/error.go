@@ -1,10 +1,14 @@
 package rownd
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
+	"net/url"
 	"strings"
 )
 
@@ -16,7 +20,11 @@ const (
 	ErrValidation     ErrKind = "validation_error"
 	ErrAPI            ErrKind = "api_error"
 	ErrNetwork        ErrKind = "network_error"
+	ErrInternal       ErrKind = "internal_error"
 	ErrNotFound       ErrKind = "not_found_error"
+	ErrConflict       ErrKind = "conflict_error"
+	ErrLastOwner      ErrKind = "last_owner_error"
+	ErrGroupCycle     ErrKind = "group_cycle_error"
 )
 
 // Error represents a custom error type for Rownd SDK
@@ -72,6 +80,30 @@ func (e *MultiError) Error() string {
 	return b.String()
 }
 
+// Unwrap returns the wrapped errors so errors.Is and errors.As can inspect them.
+func (e *MultiError) Unwrap() []error {
+	return e.errors
+}
+
+// ConflictError is returned when a read-modify-write update keeps losing to concurrent
+// modifications of the same resource.
+type ConflictError struct {
+	UserID   string
+	Attempts int
+	// Err is the last conflict observed, if it was reported by the API.
+	Err error
+}
+
+// Error implements the error interface.
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("%s: user %s was modified concurrently, gave up after %d attempts", ErrConflict, e.UserID, e.Attempts)
+}
+
+// Unwrap implements errors.Unwrap.
+func (e *ConflictError) Unwrap() error {
+	return e.Err
+}
+
 // ErrorResponse ...
 type ErrorResponse struct {
 	StatusCode   int      `json:"statusCode"`
@@ -97,6 +129,61 @@ func handleErrorResponse(response *http.Response) error {
 	if err := json.Unmarshal(responseBody, &errorResponse); err != nil {
 		return NewError(ErrAPI, fmt.Sprintf("request failed with status %d", response.StatusCode), err)
 	}
+	if errorResponse.StatusCode == 0 {
+		errorResponse.StatusCode = response.StatusCode
+	}
 
 	return errorResponse
 }
+
+// KindOf classifies an error returned by the SDK. API errors with a 404 status are reported as
+// ErrNotFound, 409 and 412 as ErrConflict, other API errors as ErrAPI, and transport failures as
+// ErrNetwork. Anything else, such as a canceled request or a response that could not be decoded,
+// is ErrInternal.
+func KindOf(err error) ErrKind {
+	if err == nil {
+		return ""
+	}
+
+	var rowndErr *Error
+	if errors.As(err, &rowndErr) {
+		return rowndErr.Kind
+	}
+
+	var conflictErr *ConflictError
+	if errors.As(err, &conflictErr) {
+		return ErrConflict
+	}
+
+	var errResp *ErrorResponse
+	if errors.As(err, &errResp) {
+		switch errResp.StatusCode {
+		case http.StatusNotFound:
+			return ErrNotFound
+		case http.StatusConflict, http.StatusPreconditionFailed:
+			return ErrConflict
+		}
+		return ErrAPI
+	}
+
+	if errors.Is(err, context.Canceled) {
+		return ErrInternal
+	}
+	var urlErr *url.Error
+	var netErr net.Error
+	if errors.As(err, &urlErr) || errors.As(err, &netErr) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return ErrNetwork
+	}
+
+	return ErrInternal
+}
+
+// isRetryable reports whether a failed request may succeed if it is sent again.
+func isRetryable(err error) bool {
+	var errResp *ErrorResponse
+	if errors.As(err, &errResp) {
+		return errResp.StatusCode == http.StatusTooManyRequests || errResp.StatusCode >= http.StatusInternalServerError
+	}
+
+	return KindOf(err) == ErrNetwork
+}
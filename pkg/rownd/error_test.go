@@ -0,0 +1,29 @@
+package rownd_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"testing"
+
+	"github.com/rownd/client-go/pkg/rownd"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKindOf(t *testing.T) {
+	for _, tc := range []struct {
+		err  error
+		want rownd.ErrKind
+	}{
+		{&url.Error{Op: "Get", URL: "https://api.rownd.io", Err: errors.New("connection refused")}, rownd.ErrNetwork},
+		{fmt.Errorf("failed to read response body: %w", io.ErrUnexpectedEOF), rownd.ErrNetwork},
+		{fmt.Errorf("failed to unmarshal response body: %w", errors.New("unexpected end of JSON input")), rownd.ErrInternal},
+		{fmt.Errorf("failed to execute request: %w", context.Canceled), rownd.ErrInternal},
+		{&url.Error{Op: "Get", URL: "https://api.rownd.io", Err: context.Canceled}, rownd.ErrInternal},
+		{rownd.NewError(rownd.ErrValidation, "bad", nil), rownd.ErrValidation},
+	} {
+		assert.Equal(t, tc.want, rownd.KindOf(tc.err), "%v", tc.err)
+	}
+}
@@ -0,0 +1,128 @@
+package rownd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// Field types used in the app schema.
+const (
+	FieldTypeString  = "string"
+	FieldTypeNumber  = "number"
+	FieldTypeBoolean = "boolean"
+	FieldTypeDate    = "date"
+	FieldTypeObject  = "object"
+	FieldTypeArray   = "array"
+)
+
+// GetField retrieves a user field and decodes it into T. Numbers decode into any numeric type,
+// dates into time.Time, and objects into structs or maps, following encoding/json rules.
+func GetField[T any](ctx context.Context, client *Client, userID, field string) (T, error) {
+	var result T
+
+	value, err := client.UserFields.Get(ctx, GetUserFieldRequest{UserID: userID, Field: field})
+	if err != nil {
+		return result, err
+	}
+
+	if err := convertFieldValue(value, &result); err != nil {
+		return result, NewError(ErrValidation, fmt.Sprintf("field %s is not a %T", field, result), err)
+	}
+
+	return result, nil
+}
+
+// SetField updates a user field with a typed value. time.Time values are sent as RFC 3339
+// strings.
+func SetField[T any](ctx context.Context, client *Client, userID, field string, value T) error {
+	return client.UserFields.Update(ctx, UpdateUserFieldRequest{
+		UserID: userID,
+		Field:  field,
+		Value:  value,
+	})
+}
+
+func convertFieldValue(value, target any) error {
+	b, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(b, target)
+}
+
+// checkFieldType validates a value against the type of the field in the app schema. Fields
+// missing from the schema, untyped fields and nil values are not checked.
+func (c *Client) checkFieldType(field string, value any) error {
+	schema, ok := c.appSchema[field]
+	if !ok || schema.Type == "" || value == nil {
+		return nil
+	}
+
+	if !matchesFieldType(schema.Type, value) {
+		return NewError(ErrValidation, fmt.Sprintf("field %s expects a %s value, got %T", field, schema.Type, value), nil)
+	}
+
+	return nil
+}
+
+func matchesFieldType(fieldType string, value any) bool {
+	switch v := value.(type) {
+	case time.Time, *time.Time:
+		return fieldType == FieldTypeDate || fieldType == FieldTypeString
+	case json.Number:
+		return fieldType == FieldTypeNumber
+	case string:
+		switch fieldType {
+		case FieldTypeString:
+			return true
+		case FieldTypeDate:
+			return isDateString(v)
+		}
+		return false
+	}
+
+	rv := reflect.ValueOf(value)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return true
+		}
+		rv = rv.Elem()
+	}
+
+	switch fieldType {
+	case FieldTypeString:
+		return rv.Kind() == reflect.String
+	case FieldTypeNumber:
+		switch rv.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+			reflect.Float32, reflect.Float64:
+			return true
+		}
+		return false
+	case FieldTypeBoolean:
+		return rv.Kind() == reflect.Bool
+	case FieldTypeDate:
+		return rv.Kind() == reflect.String && isDateString(rv.String())
+	case FieldTypeObject:
+		return rv.Kind() == reflect.Map || rv.Kind() == reflect.Struct
+	case FieldTypeArray:
+		return rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array
+	}
+
+	// unknown types are left to the API
+	return true
+}
+
+func isDateString(s string) bool {
+	for _, layout := range []string{time.RFC3339Nano, time.DateOnly} {
+		if _, err := time.Parse(layout, s); err == nil {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,151 @@
+package rownd_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rownd/client-go/pkg/rownd"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTypedFields(t *testing.T) {
+	var (
+		mu     sync.Mutex
+		fields = map[string]any{
+			"first_name":   "Jane",
+			"age":          42,
+			"subscribed":   true,
+			"birthday":     "1990-05-01T00:00:00Z",
+			"address":      map[string]any{"city": "Denver"},
+			"unknown_type": "x",
+		}
+		patches []map[string]any
+	)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/applications/", func(w http.ResponseWriter, r *http.Request) {
+		segments := pathSegments(r)
+		mu.Lock()
+		defer mu.Unlock()
+
+		switch {
+		case r.Method == http.MethodPatch:
+			var body map[string]any
+			json.NewDecoder(r.Body).Decode(&body)
+			patches = append(patches, body)
+			writeJSON(w, http.StatusOK, body)
+		case r.Method == http.MethodPut:
+			var body map[string]any
+			json.NewDecoder(r.Body).Decode(&body)
+			fields[segments[6]] = body["value"]
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			value, ok := fields[segments[6]]
+			if !ok {
+				writeJSON(w, http.StatusNotFound, map[string]any{"error": "not found"})
+				return
+			}
+			writeJSON(w, http.StatusOK, map[string]any{"value": value})
+		}
+	})
+	client := newTestClient(t, mux)
+	ctx := context.Background()
+
+	t.Run("get", func(t *testing.T) {
+		name, err := rownd.GetField[string](ctx, client, "user_1", "first_name")
+		assert.NoError(t, err)
+		assert.Equal(t, "Jane", name)
+
+		age, err := rownd.GetField[int](ctx, client, "user_1", "age")
+		assert.NoError(t, err)
+		assert.Equal(t, 42, age)
+
+		birthday, err := rownd.GetField[time.Time](ctx, client, "user_1", "birthday")
+		assert.NoError(t, err)
+		assert.Equal(t, 1990, birthday.Year())
+
+		type address struct {
+			City string `json:"city"`
+		}
+		addr, err := rownd.GetField[address](ctx, client, "user_1", "address")
+		assert.NoError(t, err)
+		assert.Equal(t, "Denver", addr.City)
+
+		_, err = rownd.GetField[bool](ctx, client, "user_1", "first_name")
+		assert.Equal(t, rownd.ErrValidation, rownd.KindOf(err))
+	})
+
+	t.Run("set", func(t *testing.T) {
+		assert.NoError(t, rownd.SetField(ctx, client, "user_1", "subscribed", false))
+		subscribed, err := rownd.GetField[bool](ctx, client, "user_1", "subscribed")
+		assert.NoError(t, err)
+		assert.False(t, subscribed)
+	})
+
+	t.Run("get fields", func(t *testing.T) {
+		values, err := client.UserFields.GetFields(ctx, rownd.GetUserFieldsRequest{
+			UserID: "user_1",
+			Fields: []string{"first_name", "age", "missing"},
+		})
+		assert.Error(t, err)
+		assert.Equal(t, map[string]any{"first_name": "Jane", "age": 42.0}, values)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		err := client.UserFields.Delete(ctx, rownd.DeleteUserFieldRequest{UserID: "user_1", Field: "first_name"})
+		assert.NoError(t, err)
+		assert.Equal(t, []map[string]any{{"data": map[string]any{"first_name": nil}}}, patches)
+	})
+}
+
+func TestFieldSchemaValidation(t *testing.T) {
+	var puts int
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/hub/app-config", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]any{"app": map[string]any{
+			"id": testAppID,
+			"schema": map[string]any{
+				"age":        map[string]any{"type": "number"},
+				"subscribed": map[string]any{"type": "boolean"},
+				"birthday":   map[string]any{"type": "date"},
+				"first_name": map[string]any{"type": "string"},
+			},
+		}})
+	})
+	mux.HandleFunc("/applications/", func(w http.ResponseWriter, r *http.Request) {
+		puts++
+		w.WriteHeader(http.StatusNoContent)
+	})
+	client := newTestClient(t, mux)
+	ctx := context.Background()
+
+	for _, tc := range []struct {
+		field string
+		value any
+		ok    bool
+	}{
+		{"age", 42, true},
+		{"age", "42", false},
+		{"subscribed", true, true},
+		{"subscribed", "yes", false},
+		{"birthday", time.Now(), true},
+		{"birthday", "2024-03-01", true},
+		{"birthday", "soon", false},
+		{"first_name", 7, false},
+		{"first_name", nil, true},
+		{"not_in_schema", 7, true},
+	} {
+		err := rownd.SetField(ctx, client, "user_1", tc.field, tc.value)
+		if tc.ok {
+			assert.NoError(t, err, "%s=%v", tc.field, tc.value)
+		} else {
+			assert.Equal(t, rownd.ErrValidation, rownd.KindOf(err), "%s=%v", tc.field, tc.value)
+		}
+	}
+	assert.Equal(t, 6, puts)
+}
@@ -2,8 +2,8 @@ package rownd
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
-	"net/http"
 	"net/url"
 	"strconv"
 	"strings"
@@ -52,6 +52,36 @@ type Group struct {
 	UpdatedAt time.Time `json:"updated_at"`
 }
 
+// DecodeGroupMeta decodes the group's metadata into T.
+func DecodeGroupMeta[T any](group *Group) (T, error) {
+	var meta T
+
+	b, err := json.Marshal(group.Meta)
+	if err != nil {
+		return meta, fmt.Errorf("failed to decode group meta: %w", err)
+	}
+	if err := json.Unmarshal(b, &meta); err != nil {
+		return meta, fmt.Errorf("failed to decode group meta: %w", err)
+	}
+
+	return meta, nil
+}
+
+// EncodeGroupMeta encodes meta, typically a struct, into the form stored in Group.Meta.
+func EncodeGroupMeta[T any](meta T) (map[string]any, error) {
+	b, err := json.Marshal(meta)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode group meta: %w", err)
+	}
+
+	var result map[string]any
+	if err := json.Unmarshal(b, &result); err != nil {
+		return nil, fmt.Errorf("failed to encode group meta: %w", err)
+	}
+
+	return result, nil
+}
+
 // groupClient ...
 type groupClient struct {
 	*Client
@@ -83,19 +113,21 @@ func (c *groupClient) Get(ctx context.Context, request GetGroupRequest) (*Group,
 		return nil, err
 	}
 
-	endpoint, err := c.rowndURL(c.baseURL, "applications", c.appID, "groups", request.GroupID)
+	endpoint, err := c.endpoint(c.endpoints.Groups.Get, c.appID, request.GroupID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to compose endpoint: %w", err)
 	}
 
 	var response *Group
-	if err := c.request(ctx, http.MethodGet, endpoint.String(), nil, &response, c.httpClientOpts...); err != nil {
+	if err := c.request(ctx, endpoint, nil, &response); err != nil {
 		return nil, err
 	}
 
 	return response, nil
 }
 
+const maxListGroupsPageSize int = 100
+
 // ListGroupsRequest ...
 type ListGroupsRequest struct {
 	// PageSize is the number of resources to return per query. Max is 100.
@@ -147,21 +179,42 @@ func (c *groupClient) List(ctx context.Context, request ListGroupsRequest) (*Lis
 		return nil, err
 	}
 
-	endpoint, err := c.rowndURL("applications", c.appID, "groups")
+	endpoint, err := c.endpoint(c.endpoints.Groups.List, c.appID)
 	if err != nil {
 		return nil, err
 	}
 
-	endpoint.RawQuery = request.params().Encode()
+	endpoint.URL.RawQuery = request.params().Encode()
 
 	var response *ListGroupsResponse
-	if err := c.request(ctx, http.MethodGet, endpoint.String(), nil, &response, c.httpClientOpts...); err != nil {
+	if err := c.request(ctx, endpoint, nil, &response); err != nil {
 		return nil, err
 	}
 
 	return response, nil
 }
 
+// eachGroup calls fn with every group of the application, following pagination.
+func (c *groupClient) eachGroup(ctx context.Context, fn func(group Group) error) error {
+	request := ListGroupsRequest{PageSize: ToPointer(maxListGroupsPageSize)}
+	for {
+		page, err := c.List(ctx, request)
+		if err != nil {
+			return err
+		}
+		for _, group := range page.Results {
+			if err := fn(group); err != nil {
+				return err
+			}
+		}
+		if len(page.Results) < maxListGroupsPageSize {
+			return nil
+		}
+
+		request.After = ToPointer(page.Results[len(page.Results)-1].ID)
+	}
+}
+
 // CreateGroupRequest ...
 type CreateGroupRequest struct {
 	// The group name.
@@ -177,6 +230,9 @@ type CreateGroupRequest struct {
 func (r CreateGroupRequest) validate() error {
 	var errs []error
 
+	if strings.TrimSpace(r.Name) == "" {
+		errs = append(errs, NewError(ErrValidation, "group name is required", nil))
+	}
 	if !r.AdmissionPolicy.validate() {
 		errs = append(errs, NewError(ErrValidation, "invalid admission policy", nil))
 	}
@@ -194,19 +250,136 @@ func (c *groupClient) Create(ctx context.Context, request CreateGroupRequest) (*
 		return nil, err
 	}
 
-	endpoint, err := c.rowndURL("applications", c.appID, "groups")
+	endpoint, err := c.endpoint(c.endpoints.Groups.Create, c.appID)
+	if err != nil {
+		return nil, err
+	}
+
+	var response *Group
+	if err := c.request(ctx, endpoint, request, &response); err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// UpdateGroupRequest replaces a group's settings.
+type UpdateGroupRequest struct {
+	GroupID string `json:"-"`
+
+	// The group name.
+	Name string `json:"name"`
+
+	// AdmissionPolicy sets whether the group is open for anyone to join or by invite only.
+	AdmissionPolicy AdmissionPolicy `json:"admission_policy"`
+
+	// Meta replaces the group's metadata.
+	Meta map[string]any `json:"meta"`
+}
+
+func (r UpdateGroupRequest) validate() error {
+	var errs []error
+
+	if r.GroupID == "" {
+		errs = append(errs, NewError(ErrValidation, "group id is required", nil))
+	}
+	if strings.TrimSpace(r.Name) == "" {
+		errs = append(errs, NewError(ErrValidation, "group name is required", nil))
+	}
+	if !r.AdmissionPolicy.validate() {
+		errs = append(errs, NewError(ErrValidation, "invalid admission policy", nil))
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+
+	return &MultiError{errors: errs}
+}
+
+// Update replaces a group's name, admission policy and metadata. Members are not affected.
+func (c *groupClient) Update(ctx context.Context, request UpdateGroupRequest) (*Group, error) {
+	if err := request.validate(); err != nil {
+		return nil, err
+	}
+
+	endpoint, err := c.endpoint(c.endpoints.Groups.Update, c.appID, request.GroupID)
 	if err != nil {
 		return nil, err
 	}
 
 	var response *Group
-	if err := c.request(ctx, http.MethodPost, endpoint.String(), request, &response, c.httpClientOpts...); err != nil {
+	if err := c.request(ctx, endpoint, request, &response); err != nil {
 		return nil, err
 	}
+	c.invalidateGroup(request.GroupID)
 
 	return response, nil
 }
 
+// PatchGroupRequest changes some of a group's settings. Nil fields are left alone.
+type PatchGroupRequest struct {
+	GroupID string
+
+	Name            *string
+	AdmissionPolicy *AdmissionPolicy
+
+	// Meta is a JSON Merge Patch (RFC 7386) of the group's metadata: a nil value removes the key
+	// and nested objects are merged.
+	Meta map[string]any
+}
+
+func (r PatchGroupRequest) validate() error {
+	var errs []error
+
+	if r.GroupID == "" {
+		errs = append(errs, NewError(ErrValidation, "group id is required", nil))
+	}
+	if r.Name != nil && strings.TrimSpace(*r.Name) == "" {
+		errs = append(errs, NewError(ErrValidation, "group name must not be empty", nil))
+	}
+	if r.AdmissionPolicy != nil && !r.AdmissionPolicy.validate() {
+		errs = append(errs, NewError(ErrValidation, "invalid admission policy", nil))
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+
+	return &MultiError{errors: errs}
+}
+
+// Patch changes some of a group's settings. The group is read, patched and written back with
+// Update, so a concurrent change to the same group between the two requests is overwritten.
+func (c *groupClient) Patch(ctx context.Context, request PatchGroupRequest) (*Group, error) {
+	if err := request.validate(); err != nil {
+		return nil, err
+	}
+
+	group, err := c.Get(ctx, GetGroupRequest{GroupID: request.GroupID})
+	if err != nil {
+		return nil, err
+	}
+
+	update := UpdateGroupRequest{
+		GroupID:         request.GroupID,
+		Name:            group.Name,
+		AdmissionPolicy: group.AdmissionPolicy,
+		Meta:            group.Meta,
+	}
+	if request.Name != nil {
+		update.Name = *request.Name
+	}
+	if request.AdmissionPolicy != nil {
+		update.AdmissionPolicy = *request.AdmissionPolicy
+	}
+	if request.Meta != nil {
+		update.Meta = ApplyMergePatch(group.Meta, request.Meta)
+	}
+
+	return c.Update(ctx, update)
+}
+
 // DeleteGroupRequest ...
 type DeleteGroupRequest struct {
 	GroupID string
@@ -231,10 +404,16 @@ func (c *groupClient) Delete(ctx context.Context, req DeleteGroupRequest) error
 	if err := req.validate(); err != nil {
 		return err
 	}
-	endpoint, err := c.rowndURL("applications", c.appID, "groups", req.GroupID)
+	endpoint, err := c.endpoint(c.endpoints.Groups.Delete, c.appID, req.GroupID)
 	if err != nil {
 		return err
 	}
 
-	return c.request(ctx, http.MethodDelete, endpoint.String(), nil, nil, c.httpClientOpts...)
+	if err := c.request(ctx, endpoint, nil, nil); err != nil {
+		return err
+	}
+	c.invalidateMembers(req.GroupID)
+	c.invalidateGroup(req.GroupID)
+
+	return nil
 }
@@ -94,7 +94,7 @@ func TestRowndGroups(t *testing.T) {
 		memberRequest := rownd.CreateGroupMemberRequest{
 			GroupID: groupID,
 			UserID:  testUserID,
-			Roles:   []string{"member"},
+			Roles:   rownd.RoleSet{"member"},
 			State:   "active",
 		}
 
@@ -117,19 +117,19 @@ func TestRowndGroups(t *testing.T) {
 			GroupID:  groupID,
 			MemberID: memberID,
 			UserID:   testUserID,
-			Roles:    []string{"member", "owner", "admin"},
+			Roles:    rownd.RoleSet{"member", "owner", "admin"},
 			State:    "active",
 		})
 		assert.NoError(t, err)
 		assert.NotNil(t, member)
-		assert.Contains(t, member.Roles, "admin")
+		assert.Contains(t, member.Roles, rownd.Role("admin"))
 	})
 
 	t.Run("create group invite", func(t *testing.T) {
 		invite, err := client.GroupInvites.Create(ctx, rownd.CreateGroupInviteRequest{
 			GroupID:     groupID,
 			Email:       "invite@example.com",
-			Roles:       []string{"member"},
+			Roles:       rownd.RoleSet{"member"},
 			RedirectURL: "https://example.com/accept",
 		})
 		assert.NoError(t, err)
@@ -164,13 +164,13 @@ func TestRowndGroups(t *testing.T) {
 		member, err := client.GroupMembers.Create(ctx, rownd.CreateGroupMemberRequest{
 			GroupID: groupID,
 			UserID:  secondUserID,
-			Roles:   []string{"member", "owner"},
+			Roles:   rownd.RoleSet{"member", "owner"},
 			State:   "active",
 		})
 		assert.NoError(t, err)
 		assert.NotNil(t, member)
 		assert.Equal(t, secondUserID, member.UserID)
-		assert.Contains(t, member.Roles, "owner")
+		assert.Contains(t, member.Roles, rownd.Role("owner"))
 	})
 
 	t.Run("list group members", func(t *testing.T) {
@@ -186,7 +186,7 @@ func TestRowndGroups(t *testing.T) {
 		for _, member := range members.Results {
 			if member.State == "active" {
 				activeMembers++
-				assert.Contains(t, member.Roles, "owner")
+				assert.Contains(t, member.Roles, rownd.Role("owner"))
 			}
 		}
 		assert.Equal(t, 2, activeMembers, "Expected to find two active group members")
@@ -196,7 +196,7 @@ func TestRowndGroups(t *testing.T) {
 		for _, member := range members.Results {
 			if member.State == "invite_pending" {
 				pendingMembers++
-				assert.Contains(t, member.Roles, "member")
+				assert.Contains(t, member.Roles, rownd.Role("member"))
 				assert.Equal(t, "invite@example.com", member.Profile["email"])
 			}
 		}
@@ -0,0 +1,337 @@
+package rownd
+
+import (
+	"context"
+	"fmt"
+)
+
+const (
+	groupMetaParentKey         = "parent_group_id"
+	groupMetaInheritedRolesKey = "inherited_roles"
+)
+
+// GroupHierarchy is the hierarchy information stored in a group's metadata.
+type GroupHierarchy struct {
+	// ParentGroupID is the ID of the parent group, empty for a top-level group.
+	ParentGroupID string `json:"parent_group_id,omitempty"`
+
+	// InheritedRoles are the roles that carry over from the parent: an active member of the
+	// parent with one of these roles has it in this group too.
+	InheritedRoles RoleSet `json:"inherited_roles,omitempty"`
+}
+
+// HierarchyOf returns the hierarchy information stored in the group's metadata.
+func HierarchyOf(group *Group) (GroupHierarchy, error) {
+	return DecodeGroupMeta[GroupHierarchy](group)
+}
+
+// MaxGroupDepth is the number of levels a group hierarchy may have, counting the top-level group.
+const MaxGroupDepth = 10
+
+// RoleResolver resolves a user's effective roles in a group. Client.Groups implements it.
+type RoleResolver interface {
+	EffectiveRoles(ctx context.Context, groupID, userID string) (RoleSet, error)
+}
+
+// CreateChildGroupRequest ...
+type CreateChildGroupRequest struct {
+	ParentGroupID  string
+	InheritedRoles RoleSet
+	Group          CreateGroupRequest
+}
+
+func (r CreateChildGroupRequest) validate() error {
+	var errs []error
+
+	if r.ParentGroupID == "" {
+		errs = append(errs, NewError(ErrValidation, "parent group id is required", nil))
+	}
+	errs = append(errs, r.InheritedRoles.validate()...)
+
+	if len(errs) == 0 {
+		return nil
+	}
+
+	return &MultiError{errors: errs}
+}
+
+// CreateChild creates a group below an existing parent group.
+func (c *groupClient) CreateChild(ctx context.Context, request CreateChildGroupRequest) (*Group, error) {
+	if err := request.validate(); err != nil {
+		return nil, err
+	}
+	parent, err := c.Get(ctx, GetGroupRequest{GroupID: request.ParentGroupID})
+	if err != nil {
+		return nil, err
+	}
+	ancestors, err := c.ancestorsOf(ctx, parent)
+	if err != nil {
+		return nil, err
+	}
+	if len(ancestors)+2 > MaxGroupDepth {
+		return nil, NewError(ErrValidation, fmt.Sprintf("group %s is at the maximum depth of %d levels", request.ParentGroupID, MaxGroupDepth), nil)
+	}
+
+	create := request.Group
+	create.Meta = map[string]any{}
+	for k, v := range request.Group.Meta {
+		create.Meta[k] = v
+	}
+	create.Meta[groupMetaParentKey] = request.ParentGroupID
+	if len(request.InheritedRoles) > 0 {
+		create.Meta[groupMetaInheritedRolesKey] = request.InheritedRoles
+	}
+
+	return c.Create(ctx, create)
+}
+
+// SetGroupParentRequest ...
+type SetGroupParentRequest struct {
+	GroupID string
+
+	// ParentGroupID is the new parent. Empty makes the group top-level.
+	ParentGroupID string
+
+	// InheritedRoles replaces the roles inherited from the parent when not nil.
+	InheritedRoles RoleSet
+}
+
+func (r SetGroupParentRequest) validate() error {
+	var errs []error
+
+	if r.GroupID == "" {
+		errs = append(errs, NewError(ErrValidation, "group id is required", nil))
+	}
+	errs = append(errs, r.InheritedRoles.validate()...)
+
+	if len(errs) == 0 {
+		return nil
+	}
+
+	return &MultiError{errors: errs}
+}
+
+// SetParent moves a group below another group. It fails with ErrGroupCycle if the new parent is
+// the group itself or one of its descendants, and with ErrValidation if the new parent is already
+// MaxGroupDepth levels deep.
+func (c *groupClient) SetParent(ctx context.Context, request SetGroupParentRequest) (*Group, error) {
+	if err := request.validate(); err != nil {
+		return nil, err
+	}
+
+	meta := map[string]any{groupMetaParentKey: nil}
+	if request.ParentGroupID != "" {
+		if request.ParentGroupID == request.GroupID {
+			return nil, NewError(ErrGroupCycle, fmt.Sprintf("group %s cannot be its own parent", request.GroupID), nil)
+		}
+		parent, err := c.Get(ctx, GetGroupRequest{GroupID: request.ParentGroupID})
+		if err != nil {
+			return nil, err
+		}
+		ancestors, err := c.ancestorsOf(ctx, parent)
+		if err != nil {
+			return nil, err
+		}
+		for _, ancestor := range ancestors {
+			if ancestor.ID == request.GroupID {
+				return nil, NewError(ErrGroupCycle, fmt.Sprintf("group %s is an ancestor of %s", request.GroupID, request.ParentGroupID), nil)
+			}
+		}
+		if len(ancestors)+2 > MaxGroupDepth {
+			return nil, NewError(ErrValidation, fmt.Sprintf("group %s is at the maximum depth of %d levels", request.ParentGroupID, MaxGroupDepth), nil)
+		}
+		meta[groupMetaParentKey] = request.ParentGroupID
+	}
+	if request.InheritedRoles != nil {
+		meta[groupMetaInheritedRolesKey] = request.InheritedRoles
+	}
+
+	return c.Patch(ctx, PatchGroupRequest{GroupID: request.GroupID, Meta: meta})
+}
+
+// Ancestors returns the group's parent, grandparent and so on up to a top-level group. Hierarchies
+// deeper than MaxGroupDepth fail with ErrValidation.
+func (c *groupClient) Ancestors(ctx context.Context, groupID string) ([]Group, error) {
+	if groupID == "" {
+		return nil, &MultiError{errors: []error{NewError(ErrValidation, "group id is required", nil)}}
+	}
+
+	group, err := c.Get(ctx, GetGroupRequest{GroupID: groupID})
+	if err != nil {
+		return nil, err
+	}
+
+	return c.ancestorsOf(ctx, group)
+}
+
+func (c *groupClient) ancestorsOf(ctx context.Context, group *Group) ([]Group, error) {
+	start := group.ID
+	ancestors := []Group{}
+	seen := map[string]bool{group.ID: true}
+	for {
+		hierarchy, err := HierarchyOf(group)
+		if err != nil {
+			return nil, err
+		}
+		if hierarchy.ParentGroupID == "" {
+			return ancestors, nil
+		}
+		if seen[hierarchy.ParentGroupID] {
+			return nil, NewError(ErrGroupCycle, fmt.Sprintf("group hierarchy above %s contains a cycle", start), nil)
+		}
+		if len(ancestors)+1 >= MaxGroupDepth {
+			return nil, NewError(ErrValidation, fmt.Sprintf("group hierarchy above %s is deeper than %d levels", start, MaxGroupDepth), nil)
+		}
+		seen[hierarchy.ParentGroupID] = true
+
+		group, err = c.cachedGroup(ctx, hierarchy.ParentGroupID)
+		if err != nil {
+			return nil, err
+		}
+		ancestors = append(ancestors, *group)
+	}
+}
+
+// Descendants returns the groups below the group, nearest first. Every group of the application
+// is listed to find them.
+func (c *groupClient) Descendants(ctx context.Context, groupID string) ([]Group, error) {
+	if groupID == "" {
+		return nil, &MultiError{errors: []error{NewError(ErrValidation, "group id is required", nil)}}
+	}
+
+	children := map[string][]Group{}
+	err := c.eachGroup(ctx, func(group Group) error {
+		hierarchy, err := HierarchyOf(&group)
+		if err != nil {
+			return err
+		}
+		if hierarchy.ParentGroupID != "" {
+			children[hierarchy.ParentGroupID] = append(children[hierarchy.ParentGroupID], group)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	descendants := []Group{}
+	seen := map[string]bool{groupID: true}
+	queue := []string{groupID}
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		for _, child := range children[id] {
+			if seen[child.ID] {
+				continue
+			}
+			seen[child.ID] = true
+			descendants = append(descendants, child)
+			queue = append(queue, child.ID)
+		}
+	}
+
+	return descendants, nil
+}
+
+// EffectiveRoles returns the user's roles in the group: the roles of an active membership in the
+// group itself plus those inherited from the parent chain. Pending members have no roles. The
+// result is empty, not an error, when the user has no roles in the group.
+//
+// Every group of the chain and its member list is read, up to MaxGroupDepth of each. With
+// WithMembershipCache they are served from the cache after the first call.
+func (c *groupClient) EffectiveRoles(ctx context.Context, groupID, userID string) (RoleSet, error) {
+	var errs []error
+	if groupID == "" {
+		errs = append(errs, NewError(ErrValidation, "group id is required", nil))
+	}
+	if userID == "" {
+		errs = append(errs, NewError(ErrValidation, "user id is required", nil))
+	}
+	if len(errs) > 0 {
+		return nil, &MultiError{errors: errs}
+	}
+
+	group, err := c.cachedGroup(ctx, groupID)
+	if err != nil {
+		return nil, err
+	}
+	ancestors, err := c.ancestorsOf(ctx, group)
+	if err != nil {
+		return nil, err
+	}
+	chain := append([]Group{*group}, ancestors...)
+
+	// walk down from the top-level group, carrying the inherited roles
+	roles := RoleSet{}
+	for i := len(chain) - 1; i >= 0; i-- {
+		hierarchy, err := HierarchyOf(&chain[i])
+		if err != nil {
+			return nil, err
+		}
+		inherited := RoleSet{}
+		for _, role := range roles {
+			if hierarchy.InheritedRoles.Has(role) {
+				inherited = inherited.Add(role)
+			}
+		}
+
+		member, err := c.GroupMembers.GetByUserID(ctx, chain[i].ID, userID)
+		switch {
+		case KindOf(err) == ErrNotFound:
+		case err != nil:
+			return nil, err
+		case member.State.isActive():
+			inherited = inherited.Add(member.Roles...)
+		}
+		roles = inherited
+	}
+
+	return roles, nil
+}
+
+// HasEffectiveRoles reports whether the user has all of the given roles in the group, directly or
+// inherited. With no roles it reports whether the user has any role at all.
+func (c *groupClient) HasEffectiveRoles(ctx context.Context, groupID, userID string, roles ...Role) (bool, error) {
+	effective, err := c.EffectiveRoles(ctx, groupID, userID)
+	if err != nil {
+		return false, err
+	}
+	if len(roles) == 0 {
+		return len(effective) > 0, nil
+	}
+	for _, role := range roles {
+		if !effective.Has(role) {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// cachedGroup retrieves the group, from the membership cache when it is enabled.
+func (c *groupClient) cachedGroup(ctx context.Context, groupID string) (*Group, error) {
+	key := cacheKeyGroupPrefix + groupID
+	if c.membershipCacheDuration > 0 {
+		if cached, found := c.cache.Get(key); found {
+			if group, ok := cached.(Group); ok {
+				return &group, nil
+			}
+		}
+	}
+
+	group, err := c.Get(ctx, GetGroupRequest{GroupID: groupID})
+	if err != nil {
+		return nil, err
+	}
+
+	if c.membershipCacheDuration > 0 {
+		c.cache.Set(key, *group, c.membershipCacheDuration)
+	}
+
+	return group, nil
+}
+
+// invalidateGroup drops the cached copy of the group.
+func (c *Client) invalidateGroup(groupID string) {
+	c.cache.Delete(cacheKeyGroupPrefix + groupID)
+}
@@ -0,0 +1,225 @@
+package rownd_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rownd/client-go/pkg/rownd"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGroupHierarchy(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		groups   map[string]map[string]any
+		requests int
+	)
+	reset := func() {
+		groups = map[string]map[string]any{
+			"org":    {"id": "org", "name": "Org", "admission_policy": "invite_only"},
+			"team":   {"id": "team", "name": "Team", "admission_policy": "invite_only", "meta": map[string]any{"parent_group_id": "org", "inherited_roles": []any{"owner"}}},
+			"squad":  {"id": "squad", "name": "Squad", "admission_policy": "invite_only", "meta": map[string]any{"parent_group_id": "team", "inherited_roles": []any{"owner", "member"}}},
+			"loop_a": {"id": "loop_a", "name": "A", "admission_policy": "open", "meta": map[string]any{"parent_group_id": "loop_b"}},
+			"loop_b": {"id": "loop_b", "name": "B", "admission_policy": "open", "meta": map[string]any{"parent_group_id": "loop_a"}},
+		}
+	}
+	members := map[string][]map[string]any{
+		"org":  {{"id": "member_1", "user_id": "user_1", "roles": []any{"owner"}, "state": "active"}},
+		"team": {{"id": "member_2", "user_id": "user_2", "roles": []any{"member"}, "state": "active"}, {"id": "member_3", "user_id": "user_3", "roles": []any{"admin"}, "state": "pending"}},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/applications/", func(w http.ResponseWriter, r *http.Request) {
+		segments := pathSegments(r)
+		mu.Lock()
+		defer mu.Unlock()
+		requests++
+
+		switch {
+		case len(segments) == 3 && r.Method == http.MethodGet:
+			ids := make([]string, 0, len(groups))
+			for id := range groups {
+				ids = append(ids, id)
+			}
+			sort.Strings(ids)
+			results := []map[string]any{}
+			for _, id := range ids {
+				results = append(results, groups[id])
+			}
+			writeJSON(w, http.StatusOK, map[string]any{"results": results})
+		case len(segments) == 3 && r.Method == http.MethodPost:
+			var body map[string]any
+			json.NewDecoder(r.Body).Decode(&body)
+			body["id"] = "new"
+			groups["new"] = body
+			writeJSON(w, http.StatusOK, body)
+		case len(segments) == 4:
+			group, ok := groups[segments[3]]
+			if !ok {
+				writeJSON(w, http.StatusNotFound, map[string]any{"message": "not found"})
+				return
+			}
+			if r.Method == http.MethodPut {
+				json.NewDecoder(r.Body).Decode(&group)
+				group["id"] = segments[3]
+				groups[segments[3]] = group
+			}
+			writeJSON(w, http.StatusOK, group)
+		case len(segments) == 5 && segments[4] == "members":
+			writeJSON(w, http.StatusOK, map[string]any{"results": members[segments[3]]})
+		}
+	})
+	client := newTestClient(t, mux)
+	ctx := context.Background()
+
+	ids := func(groups []rownd.Group) []string {
+		var result []string
+		for _, g := range groups {
+			result = append(result, g.ID)
+		}
+		return result
+	}
+
+	t.Run("ancestors and descendants", func(t *testing.T) {
+		reset()
+
+		ancestors, err := client.Groups.Ancestors(ctx, "squad")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"team", "org"}, ids(ancestors))
+
+		descendants, err := client.Groups.Descendants(ctx, "org")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"team", "squad"}, ids(descendants))
+
+		_, err = client.Groups.Ancestors(ctx, "loop_a")
+		assert.Equal(t, rownd.ErrGroupCycle, rownd.KindOf(err))
+	})
+
+	t.Run("effective roles", func(t *testing.T) {
+		reset()
+
+		for _, tc := range []struct {
+			group, user string
+			want        rownd.RoleSet
+		}{
+			{"org", "user_1", rownd.RoleSet{rownd.RoleOwner}},
+			{"squad", "user_1", rownd.RoleSet{rownd.RoleOwner}},
+			{"team", "user_2", rownd.RoleSet{rownd.RoleMember}},
+			{"squad", "user_2", rownd.RoleSet{rownd.RoleMember}},
+			{"team", "user_3", rownd.RoleSet{}},
+			{"org", "user_2", rownd.RoleSet{}},
+		} {
+			roles, err := client.Groups.EffectiveRoles(ctx, tc.group, tc.user)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, roles, "%s in %s", tc.user, tc.group)
+		}
+
+		ok, err := client.Groups.HasEffectiveRoles(ctx, "squad", "user_1", rownd.RoleOwner)
+		assert.NoError(t, err)
+		assert.True(t, ok)
+
+		ok, err = client.Groups.HasEffectiveRoles(ctx, "org", "user_2")
+		assert.NoError(t, err)
+		assert.False(t, ok)
+	})
+
+	t.Run("cached effective roles", func(t *testing.T) {
+		reset()
+		cached := newTestClient(t, mux, rownd.WithMembershipCache(time.Minute))
+
+		roles, err := cached.Groups.EffectiveRoles(ctx, "squad", "user_1")
+		assert.NoError(t, err)
+		assert.Equal(t, rownd.RoleSet{rownd.RoleOwner}, roles)
+
+		requests = 0
+		roles, err = cached.Groups.EffectiveRoles(ctx, "squad", "user_1")
+		assert.NoError(t, err)
+		assert.Equal(t, rownd.RoleSet{rownd.RoleOwner}, roles)
+		assert.Zero(t, requests, "the chain is served from the cache")
+
+		_, err = cached.Groups.SetParent(ctx, rownd.SetGroupParentRequest{GroupID: "squad"})
+		assert.NoError(t, err)
+		roles, err = cached.Groups.EffectiveRoles(ctx, "squad", "user_1")
+		assert.NoError(t, err)
+		assert.Empty(t, roles, "group changes made through the client invalidate the cache")
+	})
+
+	t.Run("depth", func(t *testing.T) {
+		reset()
+		groups["level_0"] = map[string]any{"id": "level_0", "name": "Level 0", "admission_policy": "open"}
+		for i := 1; i < rownd.MaxGroupDepth; i++ {
+			id := fmt.Sprintf("level_%d", i)
+			groups[id] = map[string]any{"id": id, "name": id, "admission_policy": "open", "meta": map[string]any{"parent_group_id": fmt.Sprintf("level_%d", i-1)}}
+		}
+		deepest := fmt.Sprintf("level_%d", rownd.MaxGroupDepth-1)
+
+		ancestors, err := client.Groups.Ancestors(ctx, deepest)
+		assert.NoError(t, err)
+		assert.Len(t, ancestors, rownd.MaxGroupDepth-1)
+
+		_, err = client.Groups.CreateChild(ctx, rownd.CreateChildGroupRequest{
+			ParentGroupID: deepest,
+			Group:         rownd.CreateGroupRequest{Name: "Too deep", AdmissionPolicy: rownd.AdmissionPolicyOpen},
+		})
+		assert.Equal(t, rownd.ErrValidation, rownd.KindOf(err))
+
+		_, err = client.Groups.SetParent(ctx, rownd.SetGroupParentRequest{GroupID: "org", ParentGroupID: deepest})
+		assert.Equal(t, rownd.ErrValidation, rownd.KindOf(err))
+
+		groups["too_deep"] = map[string]any{"id": "too_deep", "name": "Too deep", "admission_policy": "open", "meta": map[string]any{"parent_group_id": deepest}}
+		_, err = client.Groups.EffectiveRoles(ctx, "too_deep", "user_1")
+		assert.Equal(t, rownd.ErrValidation, rownd.KindOf(err), "the walk up the hierarchy is bounded")
+	})
+
+	t.Run("create child", func(t *testing.T) {
+		reset()
+
+		group, err := client.Groups.CreateChild(ctx, rownd.CreateChildGroupRequest{
+			ParentGroupID:  "team",
+			InheritedRoles: rownd.RoleSet{rownd.RoleOwner},
+			Group:          rownd.CreateGroupRequest{Name: "Squad 2", AdmissionPolicy: rownd.AdmissionPolicyOpen, Meta: map[string]any{"plan": "pro"}},
+		})
+		assert.NoError(t, err)
+		hierarchy, err := rownd.HierarchyOf(group)
+		assert.NoError(t, err)
+		assert.Equal(t, rownd.GroupHierarchy{ParentGroupID: "team", InheritedRoles: rownd.RoleSet{rownd.RoleOwner}}, hierarchy)
+		assert.Equal(t, "pro", group.Meta["plan"])
+
+		_, err = client.Groups.CreateChild(ctx, rownd.CreateChildGroupRequest{
+			ParentGroupID: "nope",
+			Group:         rownd.CreateGroupRequest{Name: "Orphan", AdmissionPolicy: rownd.AdmissionPolicyOpen},
+		})
+		assert.Equal(t, rownd.ErrNotFound, rownd.KindOf(err))
+	})
+
+	t.Run("set parent", func(t *testing.T) {
+		reset()
+
+		_, err := client.Groups.SetParent(ctx, rownd.SetGroupParentRequest{GroupID: "org", ParentGroupID: "squad"})
+		assert.Equal(t, rownd.ErrGroupCycle, rownd.KindOf(err))
+
+		_, err = client.Groups.SetParent(ctx, rownd.SetGroupParentRequest{GroupID: "org", ParentGroupID: "org"})
+		assert.Equal(t, rownd.ErrGroupCycle, rownd.KindOf(err))
+
+		group, err := client.Groups.SetParent(ctx, rownd.SetGroupParentRequest{GroupID: "squad", ParentGroupID: "org"})
+		assert.NoError(t, err)
+		hierarchy, _ := rownd.HierarchyOf(group)
+		assert.Equal(t, "org", hierarchy.ParentGroupID)
+		assert.Equal(t, rownd.RoleSet{rownd.RoleOwner, rownd.RoleMember}, hierarchy.InheritedRoles)
+
+		group, err = client.Groups.SetParent(ctx, rownd.SetGroupParentRequest{GroupID: "squad"})
+		assert.NoError(t, err)
+		hierarchy, _ = rownd.HierarchyOf(group)
+		assert.Empty(t, hierarchy.ParentGroupID)
+
+		ancestors, err := client.Groups.Ancestors(ctx, "squad")
+		assert.NoError(t, err)
+		assert.Empty(t, ancestors)
+	})
+}
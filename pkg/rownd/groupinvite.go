@@ -2,27 +2,27 @@ package rownd
 
 import (
 	"context"
-	"net/http"
 	"net/url"
+	"strconv"
 	"time"
 )
 
 // GroupInvite ...
 type GroupInvite struct {
-	ID              string    `json:"id"`
-	GroupID         string    `json:"group_id"`
-	Roles           []string  `json:"roles"`
-	State           string    `json:"state"`
-	Email           string    `json:"email,omitempty"`
-	Phone           int64     `json:"phone,omitempty"`
-	UserID          string    `json:"user_id,omitempty"`
-	UserLookupValue string    `json:"user_lookup_value,omitempty"`
-	RedirectURL     string    `json:"redirect_url,omitempty"`
-	AppVariantID    string    `json:"app_variant_id,omitempty"`
-	CreatedAt       time.Time `json:"created_at"`
-	CreatedBy       string    `json:"created_by"`
-	AcceptedBy      string    `json:"accepted_by,omitempty"`
-	EnsuredUserID   string    `json:"ensured_user_id,omitempty"`
+	ID              string      `json:"id"`
+	GroupID         string      `json:"group_id"`
+	Roles           RoleSet     `json:"roles"`
+	State           InviteState `json:"state"`
+	Email           string      `json:"email,omitempty"`
+	Phone           int64       `json:"phone,omitempty"`
+	UserID          string      `json:"user_id,omitempty"`
+	UserLookupValue string      `json:"user_lookup_value,omitempty"`
+	RedirectURL     string      `json:"redirect_url,omitempty"`
+	AppVariantID    string      `json:"app_variant_id,omitempty"`
+	CreatedAt       time.Time   `json:"created_at"`
+	CreatedBy       string      `json:"created_by"`
+	AcceptedBy      string      `json:"accepted_by,omitempty"`
+	EnsuredUserID   string      `json:"ensured_user_id,omitempty"`
 }
 
 type groupInviteClient struct {
@@ -58,13 +58,13 @@ func (c *groupInviteClient) Get(ctx context.Context, request GetGroupInviteReque
 		return nil, err
 	}
 
-	endpoint, err := c.rowndURL("applications", c.appID, "groups", request.GroupID, "invites", request.InviteID)
+	endpoint, err := c.endpoint(c.endpoints.GroupInvites.Get, c.appID, request.GroupID, request.InviteID)
 	if err != nil {
 		return nil, err
 	}
 
 	var response *GroupInvite
-	if err := c.request(ctx, http.MethodGet, endpoint.String(), nil, &response, c.httpClientOpts...); err != nil {
+	if err := c.request(ctx, endpoint, nil, &response); err != nil {
 		return nil, err
 	}
 
@@ -77,11 +77,22 @@ type ListGroupInvitesRequest struct {
 
 	// EnsuredUserID is the User ID for which the invite was created. This is not the member ID.
 	EnsuredUserID *string
+	// PageSize is the number of resources to return per query. Max is 100.
+	PageSize *int
+	// After is the ID of the last resource in the previous page. If provided, the next page of results is
+	// returned beginning with this resource ID.
+	After *string
 }
 
 func (r ListGroupInvitesRequest) params() url.Values {
 	q := url.Values{}
 
+	if r.PageSize != nil {
+		q.Add("page_size", strconv.Itoa(ToValue(r.PageSize)))
+	}
+	if r.After != nil {
+		q.Add("after", ToValue(r.After))
+	}
 	if r.EnsuredUserID != nil {
 		q.Add("ensured_user_id", ToValue(r.EnsuredUserID))
 	}
@@ -115,21 +126,43 @@ func (c *groupInviteClient) List(ctx context.Context, request ListGroupInvitesRe
 		return nil, err
 	}
 
-	endpoint, err := c.rowndURL("applications", c.appID, "groups", request.GroupID, "invites")
+	endpoint, err := c.endpoint(c.endpoints.GroupInvites.List, c.appID, request.GroupID)
 	if err != nil {
 		return nil, err
 	}
 
-	endpoint.RawQuery = request.params().Encode()
+	endpoint.URL.RawQuery = request.params().Encode()
 
 	var response *ListGroupInvitesResponse
-	if err := c.request(ctx, http.MethodDelete, endpoint.String(), nil, &response, c.httpClientOpts...); err != nil {
+	if err := c.request(ctx, endpoint, nil, &response); err != nil {
 		return nil, err
 	}
 
 	return response, nil
 }
 
+// eachInvite calls fn for every invite matching the request, following pages until the last one.
+func (c *groupInviteClient) eachInvite(ctx context.Context, request ListGroupInvitesRequest, fn func(invite GroupInvite) error) error {
+	request.PageSize = ToPointer(maxListGroupsPageSize)
+	request.After = nil
+	for {
+		page, err := c.List(ctx, request)
+		if err != nil {
+			return err
+		}
+		for _, invite := range page.Results {
+			if err := fn(invite); err != nil {
+				return err
+			}
+		}
+		if len(page.Results) < maxListGroupsPageSize {
+			return nil
+		}
+
+		request.After = ToPointer(page.Results[len(page.Results)-1].ID)
+	}
+}
+
 type CreateGroupInviteRequest struct {
 	// GroupID is Group ID.
 	GroupID string `json:"-"`
@@ -140,14 +173,14 @@ type CreateGroupInviteRequest struct {
 	// Roles are the roles into which a group member will be added upon invite acceptance (The first
 	// member invited to a group will always be created with the 'owner' role along with any
 	// additional roles specified)
-	Roles []string `json:"roles"`
+	Roles RoleSet `json:"roles"`
 
 	// RedirectURL is the relative or absolute path location to which a user
 	// will be directed after accepting the invite.
 	RedirectURL string `json:"redirect_url,omitempty"`
 
 	// Email is the email of a Rownd user in the specified application.
-	// This property is mutually exclusive with user_id and email.
+	// This property is mutually exclusive with user_id and phone.
 	Email string `json:"email,omitempty"`
 
 	// Phone is the phone number of a Rownd user in the specified application.
@@ -169,7 +202,10 @@ func (r CreateGroupInviteRequest) validate() error {
 	if len(r.Roles) == 0 {
 		errs = append(errs, NewError(ErrValidation, "roles is required", nil))
 	}
-	// TODO add validation rules for mutually exclusives fields.
+	errs = append(errs, r.Roles.validate()...)
+	if countSet(r.UserID != "", r.Email != "", r.Phone != 0) > 1 {
+		errs = append(errs, NewError(ErrValidation, "only one of user id, email and phone may be set", nil))
+	}
 
 	if len(errs) == 0 {
 		return nil
@@ -178,6 +214,16 @@ func (r CreateGroupInviteRequest) validate() error {
 	return &MultiError{errors: errs}
 }
 
+func countSet(set ...bool) int {
+	n := 0
+	for _, s := range set {
+		if s {
+			n++
+		}
+	}
+	return n
+}
+
 // GroupInviteResponse ...
 type GroupInviteResponse struct {
 	// Link is the invitation link. Your user will use this link to accept the invite.
@@ -192,14 +238,17 @@ func (c *groupInviteClient) Create(ctx context.Context, request CreateGroupInvit
 	if err := request.validate(); err != nil {
 		return nil, err
 	}
+	if err := c.checkRoles(request.Roles); err != nil {
+		return nil, err
+	}
 
-	endpoint, err := c.rowndURL("applications", c.appID, "groups", request.GroupID, "invites")
+	endpoint, err := c.endpoint(c.endpoints.GroupInvites.Create, c.appID, request.GroupID)
 	if err != nil {
 		return nil, err
 	}
 
 	var response *GroupInviteResponse
-	if err := c.request(ctx, http.MethodPost, endpoint.String(), request, &response, c.httpClientOpts...); err != nil {
+	if err := c.request(ctx, endpoint, request, &response); err != nil {
 		return nil, err
 	}
 
@@ -211,12 +260,12 @@ type UpdateGroupInviteRequest struct {
 	GroupID  string `json:"-"`
 	InviteID string `json:"-"`
 
-	UserID       string   `json:"user_id,omitempty"`
-	Roles        []string `json:"roles"`
-	RedirectURL  string   `json:"redirect_url,omitempty"`
-	Email        string   `json:"email,omitempty"`
-	Phone        int64    `json:"phone,omitempty"`
-	AppVariantID string   `json:"app_variant_id,omitempty"`
+	UserID       string  `json:"user_id,omitempty"`
+	Roles        RoleSet `json:"roles"`
+	RedirectURL  string  `json:"redirect_url,omitempty"`
+	Email        string  `json:"email,omitempty"`
+	Phone        int64   `json:"phone,omitempty"`
+	AppVariantID string  `json:"app_variant_id,omitempty"`
 }
 
 func (r *UpdateGroupInviteRequest) validate() error {
@@ -228,6 +277,10 @@ func (r *UpdateGroupInviteRequest) validate() error {
 	if r.InviteID == "" {
 		errs = append(errs, NewError(ErrValidation, "invite id is required", nil))
 	}
+	errs = append(errs, r.Roles.validate()...)
+	if countSet(r.UserID != "", r.Email != "", r.Phone != 0) > 1 {
+		errs = append(errs, NewError(ErrValidation, "only one of user id, email and phone may be set", nil))
+	}
 
 	if len(errs) == 0 {
 		return nil
@@ -241,14 +294,17 @@ func (c *groupInviteClient) Update(ctx context.Context, request UpdateGroupInvit
 	if err := request.validate(); err != nil {
 		return nil, err
 	}
+	if err := c.checkRoles(request.Roles); err != nil {
+		return nil, err
+	}
 
-	endpoint, err := c.rowndURL("applications", c.appID, "groups", request.GroupID, "invites", request.InviteID)
+	endpoint, err := c.endpoint(c.endpoints.GroupInvites.Update, c.appID, request.GroupID, request.InviteID)
 	if err != nil {
 		return nil, err
 	}
 
 	var response *GroupInvite
-	if err := c.request(ctx, http.MethodPut, endpoint.String(), request, &response, c.httpClientOpts...); err != nil {
+	if err := c.request(ctx, endpoint, request, &response); err != nil {
 		return nil, err
 	}
 
@@ -284,12 +340,12 @@ func (c *groupInviteClient) Delete(ctx context.Context, request DeleteGroupInvit
 		return err
 	}
 
-	endpoint, err := c.rowndURL("applications", c.appID, "groups", request.GroupID, "invites", request.InviteID)
+	endpoint, err := c.endpoint(c.endpoints.GroupInvites.Delete, c.appID, request.GroupID, request.InviteID)
 	if err != nil {
 		return err
 	}
 
-	if err := c.request(ctx, http.MethodDelete, endpoint.String(), nil, nil, c.httpClientOpts...); err != nil {
+	if err := c.request(ctx, endpoint, nil, nil); err != nil {
 		return err
 	}
 
@@ -0,0 +1,216 @@
+package rownd
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Invitee identifies a user to invite by exactly one of email, phone or user id.
+type Invitee struct {
+	Email  string
+	Phone  int64
+	UserID string
+
+	// Roles overrides BulkInviteOptions.Roles for this invitee.
+	Roles RoleSet
+}
+
+// key identifies the invitee for deduplication.
+func (i Invitee) key() string {
+	switch {
+	case i.UserID != "":
+		return "user:" + i.UserID
+	case i.Email != "":
+		return "email:" + strings.ToLower(strings.TrimSpace(i.Email))
+	default:
+		return "phone:" + strconv.FormatInt(i.Phone, 10)
+	}
+}
+
+func inviteKeys(invite GroupInvite) []string {
+	var keys []string
+	if invite.UserID != "" {
+		keys = append(keys, "user:"+invite.UserID)
+	}
+	if invite.EnsuredUserID != "" {
+		keys = append(keys, "user:"+invite.EnsuredUserID)
+	}
+	if invite.Email != "" {
+		keys = append(keys, "email:"+strings.ToLower(strings.TrimSpace(invite.Email)))
+	}
+	if invite.Phone != 0 {
+		keys = append(keys, "phone:"+strconv.FormatInt(invite.Phone, 10))
+	}
+	return keys
+}
+
+// InviteDelivery is handed to BulkInviteOptions.Deliver for every invite created.
+type InviteDelivery struct {
+	GroupID    string
+	Invitee    Invitee
+	Link       string
+	Invitation GroupInvite
+}
+
+// BulkInviteOptions configures BulkCreate.
+type BulkInviteOptions struct {
+	// Roles are granted to every invitee that does not set its own. Required unless every
+	// invitee sets roles.
+	Roles RoleSet
+
+	RedirectURL  string
+	AppVariantID string
+
+	// Concurrency is the number of invites created at once. Defaults to 4.
+	Concurrency int
+
+	// Deliver is called with each created invite, typically to email or text the link. A delivery
+	// error is recorded on the invitee's result; the invite itself stays created.
+	Deliver func(ctx context.Context, delivery InviteDelivery) error
+}
+
+// BulkInviteResult is the outcome for one invitee.
+type BulkInviteResult struct {
+	Invitee Invitee
+	// Link is the invitation link. It is empty when no invite was created.
+	Link       string
+	Invitation *GroupInvite
+
+	// Skipped is set when the invitee already had a pending invite, which is returned in
+	// Invitation, or was listed earlier in the same call.
+	Skipped bool
+
+	Err         error
+	Delivered   bool
+	DeliveryErr error
+}
+
+// BulkInviteReport describes the outcome of BulkCreate.
+type BulkInviteReport struct {
+	Results   []BulkInviteResult
+	Created   int
+	Skipped   int
+	Failed    int
+	Delivered int
+}
+
+// BulkCreate invites many users to a group. Invitees with a pending invite to the group, and
+// repeated invitees, are skipped. Invites are created concurrently and failures are recorded
+// per invitee; the returned error is only set when the invites could not be attempted.
+func (c *groupInviteClient) BulkCreate(ctx context.Context, groupID string, invitees []Invitee, opts BulkInviteOptions) (*BulkInviteReport, error) {
+	var errs []error
+	if groupID == "" {
+		errs = append(errs, NewError(ErrValidation, "group id is required", nil))
+	}
+	if opts.Concurrency < 0 {
+		errs = append(errs, NewError(ErrValidation, "concurrency must not be negative", nil))
+	}
+	errs = append(errs, opts.Roles.validate()...)
+	for i, invitee := range invitees {
+		if countSet(invitee.UserID != "", invitee.Email != "", invitee.Phone != 0) != 1 {
+			errs = append(errs, NewError(ErrValidation, fmt.Sprintf("invitee %d must set exactly one of email, phone and user id", i), nil))
+		}
+		if len(invitee.Roles) == 0 && len(opts.Roles) == 0 {
+			errs = append(errs, NewError(ErrValidation, fmt.Sprintf("invitee %d has no roles", i), nil))
+		}
+	}
+	if len(errs) > 0 {
+		return nil, &MultiError{errors: errs}
+	}
+
+	pending := map[string]GroupInvite{}
+	existing, err := c.List(ctx, ListGroupInvitesRequest{GroupID: groupID})
+	if err != nil {
+		return nil, err
+	}
+	for _, invite := range existing.Results {
+		if invite.State != InviteStatePending {
+			continue
+		}
+		for _, key := range inviteKeys(invite) {
+			pending[key] = invite
+		}
+	}
+
+	report := &BulkInviteReport{Results: make([]BulkInviteResult, len(invitees))}
+	var create []int
+	seen := map[string]bool{}
+	for i, invitee := range invitees {
+		result := &report.Results[i]
+		result.Invitee = invitee
+
+		key := invitee.key()
+		if invite, ok := pending[key]; ok {
+			result.Skipped = true
+			result.Invitation = &invite
+			continue
+		}
+		if seen[key] {
+			result.Skipped = true
+			continue
+		}
+		seen[key] = true
+		create = append(create, i)
+	}
+
+	concurrency := opts.Concurrency
+	if concurrency == 0 {
+		concurrency = defaultBulkConcurrency
+	}
+	runConcurrently(create, concurrency, func(i int) {
+		result := &report.Results[i]
+		invitee := result.Invitee
+
+		roles := invitee.Roles
+		if len(roles) == 0 {
+			roles = opts.Roles
+		}
+		response, err := c.Create(ctx, CreateGroupInviteRequest{
+			GroupID:      groupID,
+			UserID:       invitee.UserID,
+			Email:        invitee.Email,
+			Phone:        invitee.Phone,
+			Roles:        roles,
+			RedirectURL:  opts.RedirectURL,
+			AppVariantID: opts.AppVariantID,
+		})
+		if err != nil {
+			result.Err = err
+			return
+		}
+		result.Link = response.Link
+		result.Invitation = &response.Invitation
+
+		if opts.Deliver != nil {
+			result.DeliveryErr = opts.Deliver(ctx, InviteDelivery{
+				GroupID:    groupID,
+				Invitee:    invitee,
+				Link:       response.Link,
+				Invitation: response.Invitation,
+			})
+			result.Delivered = result.DeliveryErr == nil
+		}
+	})
+
+	for _, result := range report.Results {
+		switch {
+		case result.Skipped:
+			report.Skipped++
+		case result.Err != nil:
+			report.Failed++
+		default:
+			report.Created++
+		}
+		if result.Delivered {
+			report.Delivered++
+		}
+	}
+
+	if err := ctx.Err(); err != nil {
+		return report, err
+	}
+
+	return report, nil
+}
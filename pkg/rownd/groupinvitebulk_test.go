@@ -0,0 +1,102 @@
+package rownd_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/rownd/client-go/pkg/rownd"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGroupInviteBulkCreate(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		created []map[string]any
+	)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/applications/", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		switch r.Method {
+		case http.MethodGet:
+			writeJSON(w, http.StatusOK, map[string]any{"results": []map[string]any{
+				{"id": "invite_1", "email": "Pending@Example.com", "state": "pending", "roles": []any{"member"}},
+				{"id": "invite_2", "email": "accepted@example.com", "state": "accepted", "roles": []any{"member"}},
+			}})
+		case http.MethodPost:
+			var body map[string]any
+			json.NewDecoder(r.Body).Decode(&body)
+			if body["email"] == "broken@example.com" {
+				writeJSON(w, http.StatusBadRequest, map[string]any{"message": "bad email"})
+				return
+			}
+			created = append(created, body)
+			body["id"] = "invite_new"
+			body["state"] = "pending"
+			writeJSON(w, http.StatusOK, map[string]any{"link": "https://example.com/invite", "invitation": body})
+		}
+	})
+	client := newTestClient(t, mux)
+	ctx := context.Background()
+
+	var delivered []string
+	report, err := client.GroupInvites.BulkCreate(ctx, "group_1", []rownd.Invitee{
+		{Email: "pending@example.com"},
+		{Email: "accepted@example.com"},
+		{Email: "new@example.com", Roles: rownd.RoleSet{rownd.RoleOwner}},
+		{Email: "NEW@example.com"},
+		{Email: "broken@example.com"},
+		{Phone: 15555550100},
+	}, rownd.BulkInviteOptions{
+		Roles:       rownd.RoleSet{rownd.RoleMember},
+		Concurrency: 1,
+		Deliver: func(ctx context.Context, delivery rownd.InviteDelivery) error {
+			if delivery.Invitee.Phone != 0 {
+				return errors.New("sms unavailable")
+			}
+			delivered = append(delivered, delivery.Invitee.Email+" "+delivery.Link)
+			return nil
+		},
+	})
+	assert.NoError(t, err)
+
+	assert.Equal(t, 3, report.Created)
+	assert.Equal(t, 2, report.Skipped)
+	assert.Equal(t, 1, report.Failed)
+	assert.Equal(t, 2, report.Delivered)
+	assert.Len(t, created, 3)
+
+	assert.True(t, report.Results[0].Skipped)
+	assert.Equal(t, "invite_1", report.Results[0].Invitation.ID)
+	assert.Empty(t, report.Results[0].Link)
+	assert.Equal(t, rownd.RoleSet{rownd.RoleOwner}, report.Results[2].Invitation.Roles)
+	assert.True(t, report.Results[3].Skipped, "repeated invitee")
+	assert.Nil(t, report.Results[3].Invitation)
+	assert.Error(t, report.Results[4].Err)
+	assert.False(t, report.Results[5].Delivered)
+	assert.EqualError(t, report.Results[5].DeliveryErr, "sms unavailable")
+	assert.Equal(t, []string{"accepted@example.com https://example.com/invite", "new@example.com https://example.com/invite"}, delivered)
+
+	t.Run("validation", func(t *testing.T) {
+		_, err := client.GroupInvites.BulkCreate(ctx, "group_1", []rownd.Invitee{
+			{Email: "a@example.com", UserID: "user_a"},
+			{Email: "b@example.com"},
+		}, rownd.BulkInviteOptions{})
+		assert.ErrorContains(t, err, "invitee 0 must set exactly one")
+		assert.ErrorContains(t, err, "invitee 1 has no roles")
+
+		_, err = client.GroupInvites.Create(ctx, rownd.CreateGroupInviteRequest{
+			GroupID: "group_1",
+			Email:   "a@example.com",
+			Phone:   15555550100,
+			Roles:   rownd.RoleSet{rownd.RoleMember},
+		})
+		assert.ErrorContains(t, err, "only one of user id, email and phone")
+	})
+}
@@ -0,0 +1,284 @@
+package rownd
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Age returns how long ago the invite was created, relative to now.
+func (i GroupInvite) Age(now time.Time) time.Duration {
+	return now.Sub(i.CreatedAt)
+}
+
+// ListStaleInvitesRequest ...
+type ListStaleInvitesRequest struct {
+	// OlderThan is the minimum age of the pending invites returned.
+	OlderThan time.Duration
+
+	// GroupIDs limits the search to these groups. All groups are searched when empty.
+	GroupIDs []string
+
+	// Now is used as the current time. Defaults to time.Now.
+	Now func() time.Time
+}
+
+func (r ListStaleInvitesRequest) validate() error {
+	var errs []error
+
+	if r.OlderThan <= 0 {
+		errs = append(errs, NewError(ErrValidation, "older than must be positive", nil))
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+
+	return &MultiError{errors: errs}
+}
+
+// ListStale returns the pending invites created more than OlderThan ago. Invites without a
+// creation time are never considered stale.
+func (c *groupInviteClient) ListStale(ctx context.Context, request ListStaleInvitesRequest) ([]GroupInvite, error) {
+	if err := request.validate(); err != nil {
+		return nil, err
+	}
+
+	now := time.Now
+	if request.Now != nil {
+		now = request.Now
+	}
+	cutoff := now().Add(-request.OlderThan)
+
+	stale := []GroupInvite{}
+	err := c.eachInviteGroup(ctx, request.GroupIDs, func(groupID string, invites []GroupInvite) {
+		for _, invite := range invites {
+			if invite.State == InviteStatePending && !invite.CreatedAt.IsZero() && invite.CreatedAt.Before(cutoff) {
+				stale = append(stale, invite)
+			}
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return stale, nil
+}
+
+// eachInviteGroup calls fn with the invites of each group in groupIDs, or of every group when
+// groupIDs is empty. Invites missing a group id get it filled in.
+func (c *groupInviteClient) eachInviteGroup(ctx context.Context, groupIDs []string, fn func(groupID string, invites []GroupInvite)) error {
+	visit := func(groupID string) error {
+		invites := []GroupInvite{}
+		err := c.eachInvite(ctx, ListGroupInvitesRequest{GroupID: groupID}, func(invite GroupInvite) error {
+			if invite.GroupID == "" {
+				invite.GroupID = groupID
+			}
+			invites = append(invites, invite)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		fn(groupID, invites)
+		return nil
+	}
+
+	if len(groupIDs) > 0 {
+		for _, groupID := range groupIDs {
+			if err := visit(groupID); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	return c.Groups.eachGroup(ctx, func(group Group) error {
+		return visit(group.ID)
+	})
+}
+
+// RevokeInvitesOptions configures RevokeMany.
+type RevokeInvitesOptions struct {
+	// Concurrency is the number of invites deleted at once. Defaults to 4.
+	Concurrency int
+}
+
+// RevokeInviteResult is the outcome for one invite.
+type RevokeInviteResult struct {
+	Invite GroupInvite
+
+	// Skipped is set when the invite is no longer pending and was left alone.
+	Skipped bool
+	Err     error
+}
+
+// RevokeInvitesReport describes the outcome of RevokeMany.
+type RevokeInvitesReport struct {
+	Results []RevokeInviteResult
+	Revoked int
+	Skipped int
+	Failed  int
+}
+
+// RevokeMany deletes the given pending invites, typically the result of ListStale. Failures are
+// recorded per invite.
+func (c *groupInviteClient) RevokeMany(ctx context.Context, invites []GroupInvite, opts RevokeInvitesOptions) (*RevokeInvitesReport, error) {
+	var errs []error
+	if opts.Concurrency < 0 {
+		errs = append(errs, NewError(ErrValidation, "concurrency must not be negative", nil))
+	}
+	for _, invite := range invites {
+		if invite.GroupID == "" || invite.ID == "" {
+			errs = append(errs, NewError(ErrValidation, "invites must have a group id and an id", nil))
+			break
+		}
+	}
+	if len(errs) > 0 {
+		return nil, &MultiError{errors: errs}
+	}
+
+	report := &RevokeInvitesReport{Results: make([]RevokeInviteResult, len(invites))}
+	var revoke []int
+	for i, invite := range invites {
+		report.Results[i].Invite = invite
+		if invite.State != "" && invite.State != InviteStatePending {
+			report.Results[i].Skipped = true
+			continue
+		}
+		revoke = append(revoke, i)
+	}
+
+	concurrency := opts.Concurrency
+	if concurrency == 0 {
+		concurrency = defaultBulkConcurrency
+	}
+	runConcurrently(revoke, concurrency, func(i int) {
+		result := &report.Results[i]
+		result.Err = c.Delete(ctx, DeleteGroupInviteRequest{GroupID: result.Invite.GroupID, InviteID: result.Invite.ID})
+	})
+
+	for _, result := range report.Results {
+		switch {
+		case result.Skipped:
+			report.Skipped++
+		case result.Err != nil:
+			report.Failed++
+		default:
+			report.Revoked++
+		}
+	}
+
+	if err := ctx.Err(); err != nil {
+		return report, err
+	}
+
+	return report, nil
+}
+
+// RegenerateGroupInviteRequest ...
+type RegenerateGroupInviteRequest struct {
+	GroupID  string
+	InviteID string
+}
+
+func (r RegenerateGroupInviteRequest) validate() error {
+	var errs []error
+
+	if r.GroupID == "" {
+		errs = append(errs, NewError(ErrValidation, "group id is required", nil))
+	}
+	if r.InviteID == "" {
+		errs = append(errs, NewError(ErrValidation, "invite id is required", nil))
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+
+	return &MultiError{errors: errs}
+}
+
+// Regenerate replaces a pending invite with a fresh one for the same invitee, roles, redirect URL
+// and app variant, returning the new link. The old invite is deleted first, so the invitee never
+// holds two pending invites. Invites without a user id, email or phone are rejected before anything
+// is deleted. If the delete fails nothing is created; if the create fails the returned error says
+// that the old invite is gone and the invitee must be invited again.
+func (c *groupInviteClient) Regenerate(ctx context.Context, request RegenerateGroupInviteRequest) (*GroupInviteResponse, error) {
+	if err := request.validate(); err != nil {
+		return nil, err
+	}
+
+	invite, err := c.Get(ctx, GetGroupInviteRequest{GroupID: request.GroupID, InviteID: request.InviteID})
+	if err != nil {
+		return nil, err
+	}
+	if invite.State != InviteStatePending {
+		return nil, NewError(ErrValidation, "only pending invites can be regenerated", nil)
+	}
+
+	create := CreateGroupInviteRequest{
+		GroupID:      request.GroupID,
+		Roles:        invite.Roles,
+		RedirectURL:  invite.RedirectURL,
+		AppVariantID: invite.AppVariantID,
+	}
+	switch {
+	case invite.UserID != "":
+		create.UserID = invite.UserID
+	case invite.Email != "":
+		create.Email = invite.Email
+	case invite.Phone != 0:
+		create.Phone = invite.Phone
+	default:
+		return nil, NewError(ErrValidation, fmt.Sprintf("invite %s has no user id, email or phone to reissue it to", request.InviteID), nil)
+	}
+
+	if err := c.Delete(ctx, DeleteGroupInviteRequest{GroupID: request.GroupID, InviteID: request.InviteID}); err != nil {
+		return nil, fmt.Errorf("failed to delete invite %s: %w", request.InviteID, err)
+	}
+
+	response, err := c.Create(ctx, create)
+	if err != nil {
+		return nil, fmt.Errorf("invite %s was deleted but its replacement could not be created: %w", request.InviteID, err)
+	}
+
+	return response, nil
+}
+
+// InviteStats summarizes the invites of a group.
+type InviteStats struct {
+	GroupID  string
+	Total    int
+	Pending  int
+	Accepted int
+
+	// AcceptanceRate is Accepted divided by Total, or zero for a group without invites.
+	AcceptanceRate float64
+}
+
+// Stats reports invite acceptance per group for the given groups, or for every group when none
+// are given.
+func (c *groupInviteClient) Stats(ctx context.Context, groupIDs ...string) ([]InviteStats, error) {
+	stats := []InviteStats{}
+	err := c.eachInviteGroup(ctx, groupIDs, func(groupID string, invites []GroupInvite) {
+		s := InviteStats{GroupID: groupID, Total: len(invites)}
+		for _, invite := range invites {
+			switch invite.State {
+			case InviteStatePending:
+				s.Pending++
+			case InviteStateAccepted:
+				s.Accepted++
+			}
+		}
+		if s.Total > 0 {
+			s.AcceptanceRate = float64(s.Accepted) / float64(s.Total)
+		}
+		stats = append(stats, s)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
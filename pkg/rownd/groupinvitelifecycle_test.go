@@ -0,0 +1,185 @@
+package rownd_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rownd/client-go/pkg/rownd"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGroupInviteLifecycle(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	old := now.Add(-30 * 24 * time.Hour)
+	recent := now.Add(-time.Hour)
+
+	var (
+		mu      sync.Mutex
+		calls   []string
+		fail    string
+		invites map[string][]map[string]any
+	)
+	reset := func() {
+		calls = nil
+		fail = ""
+		invites = map[string][]map[string]any{
+			"group_1": {
+				{"id": "invite_1", "email": "a@example.com", "state": "pending", "roles": []any{"admin"}, "redirect_url": "/welcome", "app_variant_id": "variant_1", "created_at": old},
+				{"id": "invite_2", "email": "b@example.com", "state": "pending", "roles": []any{"member"}, "created_at": recent},
+				{"id": "invite_3", "email": "c@example.com", "state": "accepted", "roles": []any{"member"}, "created_at": old},
+			},
+			"group_2": {
+				{"id": "invite_4", "phone": 15555550100, "state": "pending", "roles": []any{"member"}, "created_at": old},
+				{"id": "invite_5", "state": "pending", "roles": []any{"member"}, "created_at": recent},
+			},
+		}
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/applications/", func(w http.ResponseWriter, r *http.Request) {
+		segments := pathSegments(r)
+		mu.Lock()
+		defer mu.Unlock()
+
+		if r.Method == fail {
+			calls = append(calls, "fail "+r.Method)
+			writeJSON(w, http.StatusInternalServerError, map[string]any{"message": "boom"})
+			return
+		}
+
+		switch {
+		case len(segments) == 3:
+			writeJSON(w, http.StatusOK, map[string]any{"results": []map[string]any{{"id": "group_1"}, {"id": "group_2"}}})
+		case len(segments) == 5 && r.Method == http.MethodGet:
+			writePage(w, r, invites[segments[3]])
+		case len(segments) == 5 && r.Method == http.MethodPost:
+			var body map[string]any
+			json.NewDecoder(r.Body).Decode(&body)
+			calls = append(calls, "create "+body["email"].(string))
+			body["id"] = "invite_new"
+			body["state"] = "pending"
+			invites[segments[3]] = append(invites[segments[3]], body)
+			writeJSON(w, http.StatusOK, map[string]any{"link": "https://example.com/new", "invitation": body})
+		case r.Method == http.MethodGet:
+			for _, invite := range invites[segments[3]] {
+				if invite["id"] == segments[5] {
+					writeJSON(w, http.StatusOK, invite)
+					return
+				}
+			}
+			writeJSON(w, http.StatusNotFound, map[string]any{"message": "not found"})
+		case r.Method == http.MethodDelete:
+			calls = append(calls, "delete "+segments[5])
+			w.WriteHeader(http.StatusNoContent)
+		}
+	})
+	client := newTestClient(t, mux)
+	ctx := context.Background()
+
+	t.Run("list stale", func(t *testing.T) {
+		reset()
+
+		stale, err := client.GroupInvites.ListStale(ctx, rownd.ListStaleInvitesRequest{
+			OlderThan: 7 * 24 * time.Hour,
+			Now:       func() time.Time { return now },
+		})
+		assert.NoError(t, err)
+		assert.Len(t, stale, 2)
+		assert.Equal(t, "invite_1", stale[0].ID)
+		assert.Equal(t, "group_1", stale[0].GroupID)
+		assert.Equal(t, "invite_4", stale[1].ID)
+		assert.Equal(t, "group_2", stale[1].GroupID)
+		assert.Equal(t, 30*24*time.Hour, stale[0].Age(now))
+
+		stale, err = client.GroupInvites.ListStale(ctx, rownd.ListStaleInvitesRequest{
+			OlderThan: 7 * 24 * time.Hour,
+			GroupIDs:  []string{"group_2"},
+			Now:       func() time.Time { return now },
+		})
+		assert.NoError(t, err)
+		assert.Len(t, stale, 1)
+
+		_, err = client.GroupInvites.ListStale(ctx, rownd.ListStaleInvitesRequest{})
+		assert.Equal(t, rownd.ErrValidation, rownd.KindOf(err))
+	})
+
+	t.Run("revoke many", func(t *testing.T) {
+		reset()
+
+		report, err := client.GroupInvites.RevokeMany(ctx, []rownd.GroupInvite{
+			{ID: "invite_1", GroupID: "group_1", State: rownd.InviteStatePending},
+			{ID: "invite_3", GroupID: "group_1", State: rownd.InviteStateAccepted},
+		}, rownd.RevokeInvitesOptions{})
+		assert.NoError(t, err)
+		assert.Equal(t, 1, report.Revoked)
+		assert.Equal(t, 1, report.Skipped)
+		assert.Equal(t, []string{"delete invite_1"}, calls)
+
+		_, err = client.GroupInvites.RevokeMany(ctx, []rownd.GroupInvite{{ID: "invite_1"}}, rownd.RevokeInvitesOptions{})
+		assert.Equal(t, rownd.ErrValidation, rownd.KindOf(err))
+	})
+
+	t.Run("regenerate", func(t *testing.T) {
+		reset()
+
+		response, err := client.GroupInvites.Regenerate(ctx, rownd.RegenerateGroupInviteRequest{GroupID: "group_1", InviteID: "invite_1"})
+		assert.NoError(t, err)
+		assert.Equal(t, "https://example.com/new", response.Link)
+		assert.Equal(t, rownd.RoleSet{"admin"}, response.Invitation.Roles)
+		assert.Equal(t, "/welcome", response.Invitation.RedirectURL)
+		assert.Equal(t, "variant_1", response.Invitation.AppVariantID)
+		assert.Equal(t, []string{"delete invite_1", "create a@example.com"}, calls)
+
+		_, err = client.GroupInvites.Regenerate(ctx, rownd.RegenerateGroupInviteRequest{GroupID: "group_1", InviteID: "invite_3"})
+		assert.Equal(t, rownd.ErrValidation, rownd.KindOf(err))
+	})
+
+	t.Run("regenerate without invitee", func(t *testing.T) {
+		reset()
+
+		_, err := client.GroupInvites.Regenerate(ctx, rownd.RegenerateGroupInviteRequest{GroupID: "group_2", InviteID: "invite_5"})
+		assert.Equal(t, rownd.ErrValidation, rownd.KindOf(err))
+		assert.Empty(t, calls, "the invite is kept")
+	})
+
+	t.Run("regenerate delete fails", func(t *testing.T) {
+		reset()
+		fail = http.MethodDelete
+
+		_, err := client.GroupInvites.Regenerate(ctx, rownd.RegenerateGroupInviteRequest{GroupID: "group_1", InviteID: "invite_1"})
+		assert.Equal(t, rownd.ErrAPI, rownd.KindOf(err))
+		assert.Equal(t, []string{"fail DELETE"}, calls, "nothing is created when the old invite remains")
+	})
+
+	t.Run("regenerate create fails", func(t *testing.T) {
+		reset()
+		fail = http.MethodPost
+
+		_, err := client.GroupInvites.Regenerate(ctx, rownd.RegenerateGroupInviteRequest{GroupID: "group_1", InviteID: "invite_1"})
+		assert.Equal(t, rownd.ErrAPI, rownd.KindOf(err))
+		assert.ErrorContains(t, err, "invite invite_1 was deleted")
+		assert.Equal(t, []string{"delete invite_1", "fail POST"}, calls)
+	})
+
+	t.Run("stats", func(t *testing.T) {
+		reset()
+		// a full page of accepted invites comes before the rest of group_1's invites
+		var accepted []map[string]any
+		for i := 0; i < 100; i++ {
+			accepted = append(accepted, map[string]any{"id": fmt.Sprintf("accepted_%d", i), "state": "accepted", "created_at": old})
+		}
+		invites["group_1"] = append(accepted, invites["group_1"]...)
+
+		stats, err := client.GroupInvites.Stats(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, []rownd.InviteStats{
+			{GroupID: "group_1", Total: 103, Pending: 2, Accepted: 101, AcceptanceRate: 101.0 / 103},
+			{GroupID: "group_2", Total: 2, Pending: 2},
+		}, stats)
+	})
+}
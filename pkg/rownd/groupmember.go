@@ -2,7 +2,7 @@ package rownd
 
 import (
 	"context"
-	"net/http"
+	"fmt"
 	"net/url"
 	"strconv"
 	"strings"
@@ -22,8 +22,8 @@ type groupMemberClient struct {
 type GroupMember struct {
 	ID        string                 `json:"id"`
 	UserID    string                 `json:"user_id"`
-	Roles     []string               `json:"roles"`
-	State     string                 `json:"state"`
+	Roles     RoleSet                `json:"roles"`
+	State     MemberState            `json:"state"`
 	InvitedBy string                 `json:"invited_by,omitempty"`
 	AddedBy   string                 `json:"added_by,omitempty"`
 	Profile   map[string]interface{} `json:"profile,omitempty"`
@@ -59,13 +59,13 @@ func (c *groupMemberClient) Get(ctx context.Context, request GetGroupMemberReque
 		return nil, err
 	}
 
-	endpoint, err := c.rowndURL("applications", c.appID, "groups", request.GroupID, "members", request.MemberID)
+	endpoint, err := c.endpoint(c.endpoints.GroupMembers.Get, c.appID, request.GroupID, request.MemberID)
 	if err != nil {
 		return nil, err
 	}
 
 	var response *GroupMember
-	if err := c.request(ctx, http.MethodGet, endpoint.String(), nil, &response, c.httpClientOpts...); err != nil {
+	if err := c.request(ctx, endpoint, nil, &response); err != nil {
 		return nil, err
 	}
 
@@ -127,28 +127,49 @@ func (c *groupMemberClient) List(ctx context.Context, request ListGroupMembersRe
 		return nil, err
 	}
 
-	endpoint, err := c.rowndURL("applications", c.appID, "groups", request.GroupID, "members")
+	endpoint, err := c.endpoint(c.endpoints.GroupMembers.List, c.appID, request.GroupID)
 	if err != nil {
 		return nil, err
 	}
 
-	endpoint.RawQuery = request.params().Encode()
+	endpoint.URL.RawQuery = request.params().Encode()
 
 	var response *ListGroupMembersResponse
-	if err := c.request(ctx, http.MethodGet, endpoint.String(), nil, &response, c.httpClientOpts...); err != nil {
+	if err := c.request(ctx, endpoint, nil, &response); err != nil {
 		return nil, err
 	}
 
 	return response, nil
 }
 
+// eachMember calls fn with every member of the group, following pagination.
+func (c *groupMemberClient) eachMember(ctx context.Context, groupID string, fn func(member GroupMember) error) error {
+	request := ListGroupMembersRequest{GroupID: groupID, PageSize: ToPointer(maxListGroupsPageSize)}
+	for {
+		page, err := c.List(ctx, request)
+		if err != nil {
+			return err
+		}
+		for _, member := range page.Results {
+			if err := fn(member); err != nil {
+				return err
+			}
+		}
+		if len(page.Results) < maxListGroupsPageSize {
+			return nil
+		}
+
+		request.After = ToPointer(page.Results[len(page.Results)-1].ID)
+	}
+}
+
 // CreateGroupMemberRequest ...
 type CreateGroupMemberRequest struct {
 	GroupID string `json:"-"`
 
-	UserID string   `json:"user_id"`
-	Roles  []string `json:"roles"`
-	State  string   `json:"state"`
+	UserID string      `json:"user_id"`
+	Roles  RoleSet     `json:"roles"`
+	State  MemberState `json:"state"`
 }
 
 func (r CreateGroupMemberRequest) validate() error {
@@ -157,8 +178,13 @@ func (r CreateGroupMemberRequest) validate() error {
 	if r.GroupID == "" {
 		errs = append(errs, NewError(ErrValidation, "group id is required", nil))
 	}
-
-	// TODO validate other fields
+	if r.UserID == "" {
+		errs = append(errs, NewError(ErrValidation, "user id is required", nil))
+	}
+	errs = append(errs, r.Roles.validate()...)
+	if r.State != "" && !r.State.validate() {
+		errs = append(errs, NewError(ErrValidation, fmt.Sprintf("invalid member state %q", r.State), nil))
+	}
 
 	if len(errs) == 0 {
 		return nil
@@ -173,8 +199,11 @@ func (c *groupMemberClient) Create(ctx context.Context, request CreateGroupMembe
 		c.logger.Printf("Validation error: %v", err)
 		return nil, err
 	}
+	if err := c.checkRoles(request.Roles); err != nil {
+		return nil, err
+	}
 
-	endpoint, err := c.rowndURL("applications", c.appID, "groups", request.GroupID, "members")
+	endpoint, err := c.endpoint(c.endpoints.GroupMembers.Create, c.appID, request.GroupID)
 	if err != nil {
 		c.logger.Printf("URL creation error: %v", err)
 		return nil, err
@@ -182,14 +211,15 @@ func (c *groupMemberClient) Create(ctx context.Context, request CreateGroupMembe
 
 	// Log request details
 	c.logger.Printf("Creating group member - Group ID: %s, User ID: %s", request.GroupID, request.UserID)
-	c.logger.Printf("POST Request URL: %s", endpoint.String())
+	c.logger.Printf("POST Request URL: %s", endpoint.URL.String())
 	c.logger.Printf("Request body: %+v", request)
 
 	var response *GroupMember
-	if err := c.request(ctx, http.MethodPost, endpoint.String(), request, &response, c.httpClientOpts...); err != nil {
+	if err := c.request(ctx, endpoint, request, &response); err != nil {
 		c.logger.Printf("API error: %v", err)
 		return nil, err
 	}
+	c.invalidateMembers(request.GroupID)
 
 	c.logger.Printf("Response: %+v", response)
 	return response, nil
@@ -200,9 +230,9 @@ type UpdateGroupMemberRequest struct {
 	GroupID  string `json:"-"`
 	MemberID string `json:"-"`
 
-	UserID string   `json:"user_id"`
-	Roles  []string `json:"roles"`
-	State  string   `json:"state"`
+	UserID string      `json:"user_id"`
+	Roles  RoleSet     `json:"roles"`
+	State  MemberState `json:"state"`
 }
 
 func (r UpdateGroupMemberRequest) validate() error {
@@ -214,6 +244,10 @@ func (r UpdateGroupMemberRequest) validate() error {
 	if r.MemberID == "" {
 		errs = append(errs, NewError(ErrValidation, "member id is required", nil))
 	}
+	errs = append(errs, r.Roles.validate()...)
+	if r.State != "" && !r.State.validate() {
+		errs = append(errs, NewError(ErrValidation, fmt.Sprintf("invalid member state %q", r.State), nil))
+	}
 
 	if len(errs) == 0 {
 		return nil
@@ -222,27 +256,43 @@ func (r UpdateGroupMemberRequest) validate() error {
 	return &MultiError{errors: errs}
 }
 
-// Update updates an existing group member
+// Update updates an existing group member. Taking the owner role away from the group's only active
+// owner, or making that owner inactive, fails with an ErrLastOwner error before the API is called.
 func (c *groupMemberClient) Update(ctx context.Context, request UpdateGroupMemberRequest) (*GroupMember, error) {
 	if err := request.validate(); err != nil {
 		c.logger.Printf("Validation error: %v", err)
 		return nil, err
 	}
+	if err := c.checkRoles(request.Roles); err != nil {
+		return nil, err
+	}
+	updated := GroupMember{Roles: request.Roles, State: request.State}
+	if !updated.isActiveOwner() {
+		if err := c.checkOwnerRemains(ctx, request.GroupID, request.MemberID); err != nil {
+			return nil, err
+		}
+	}
 
-	endpoint, err := c.rowndURL("applications", c.appID, "groups", request.GroupID, "members", request.MemberID)
+	return c.update(ctx, request)
+}
+
+// update sends a validated member update without checking that an owner remains.
+func (c *groupMemberClient) update(ctx context.Context, request UpdateGroupMemberRequest) (*GroupMember, error) {
+	endpoint, err := c.endpoint(c.endpoints.GroupMembers.Update, c.appID, request.GroupID, request.MemberID)
 	if err != nil {
 		c.logger.Printf("URL creation error: %v", err)
 		return nil, err
 	}
 
 	c.logger.Printf("Updating member - Member ID: %s, New roles: %v", request.MemberID, request.Roles)
-	c.logger.Printf("PUT Request URL: %s", endpoint.String())
+	c.logger.Printf("PUT Request URL: %s", endpoint.URL.String())
 
 	var response *GroupMember
-	if err := c.request(ctx, http.MethodPut, endpoint.String(), request, &response, c.httpClientOpts...); err != nil {
+	if err := c.request(ctx, endpoint, request, &response); err != nil {
 		c.logger.Printf("Update error: %v", err)
 		return nil, err
 	}
+	c.invalidateMembers(request.GroupID)
 
 	c.logger.Printf("Update response: %+v", response)
 	return response, nil
@@ -271,25 +321,149 @@ func (r DeleteGroupMemberRequest) validate() error {
 	return &MultiError{errors: errs}
 }
 
-// Delete removes a member from a group
+// Delete removes a member from a group. Removing the group's only active owner fails with an
+// ErrLastOwner error before the API is called; delete the group instead.
 func (c *groupMemberClient) Delete(ctx context.Context, req DeleteGroupMemberRequest) error {
 	if err := req.validate(); err != nil {
 		return err
 	}
+	if err := c.checkOwnerRemains(ctx, req.GroupID, req.MemberID); err != nil {
+		return err
+	}
 
-	endpoint, err := c.rowndURL("applications", c.appID, "groups", req.GroupID, "members", req.MemberID)
+	return c.remove(ctx, req)
+}
+
+// remove sends a validated member removal without checking that an owner remains.
+func (c *groupMemberClient) remove(ctx context.Context, req DeleteGroupMemberRequest) error {
+	endpoint, err := c.endpoint(c.endpoints.GroupMembers.Delete, c.appID, req.GroupID, req.MemberID)
 	if err != nil {
 		return err
 	}
 
 	c.logger.Printf("Deleting group member - Group ID: %s, Member ID: %s", req.GroupID, req.MemberID)
-	c.logger.Printf("DELETE Request URL: %s", endpoint.String())
+	c.logger.Printf("DELETE Request URL: %s", endpoint.URL.String())
 
 	// Pass nil for the response parameter since DELETE returns no content
-	if err := c.request(ctx, http.MethodDelete, endpoint.String(), nil, nil, c.httpClientOpts...); err != nil {
+	if err := c.request(ctx, endpoint, nil, nil); err != nil {
 		c.logger.Printf("Delete error: %v", err)
 		return err
 	}
+	c.invalidateMembers(req.GroupID)
 
 	return nil
 }
+
+// checkOwnerRemains returns an ErrLastOwner error if the member is the only active owner of the
+// group. The group is listed only when the member is an active owner.
+func (c *groupMemberClient) checkOwnerRemains(ctx context.Context, groupID, memberID string) error {
+	target, err := c.Get(ctx, GetGroupMemberRequest{GroupID: groupID, MemberID: memberID})
+	if err != nil {
+		return err
+	}
+	if !target.isActiveOwner() {
+		return nil
+	}
+
+	var otherOwners int
+	err = c.eachMember(ctx, groupID, func(member GroupMember) error {
+		if member.ID != memberID && member.isActiveOwner() {
+			otherOwners++
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if otherOwners == 0 {
+		return NewError(ErrLastOwner, fmt.Sprintf("member %s is the last owner of group %s", memberID, groupID), nil)
+	}
+
+	return nil
+}
+
+// isActiveOwner reports whether the member holds the owner role and is active.
+func (m GroupMember) isActiveOwner() bool {
+	return m.Roles.Has(RoleOwner) && m.State.isActive()
+}
+
+// TransferOwnership moves the owner role from one member of a group to another. The new owner is
+// promoted before the previous owner is demoted, so the group always has an owner. If the
+// demotion fails, the promotion is rolled back.
+func (c *groupMemberClient) TransferOwnership(ctx context.Context, groupID, fromMemberID, toMemberID string) error {
+	var errs []error
+	if groupID == "" {
+		errs = append(errs, NewError(ErrValidation, "group id is required", nil))
+	}
+	if fromMemberID == "" || toMemberID == "" {
+		errs = append(errs, NewError(ErrValidation, "both member ids are required", nil))
+	}
+	if fromMemberID != "" && fromMemberID == toMemberID {
+		errs = append(errs, NewError(ErrValidation, "cannot transfer ownership to the same member", nil))
+	}
+	if len(errs) > 0 {
+		return &MultiError{errors: errs}
+	}
+
+	from, err := c.Get(ctx, GetGroupMemberRequest{GroupID: groupID, MemberID: fromMemberID})
+	if err != nil {
+		return err
+	}
+	to, err := c.Get(ctx, GetGroupMemberRequest{GroupID: groupID, MemberID: toMemberID})
+	if err != nil {
+		return err
+	}
+
+	if !from.Roles.Has(RoleOwner) {
+		return NewError(ErrValidation, fmt.Sprintf("member %s is not an owner of group %s", fromMemberID, groupID), nil)
+	}
+	if !to.State.isActive() {
+		return NewError(ErrValidation, fmt.Sprintf("member %s is not active", toMemberID), nil)
+	}
+
+	promoted := !to.Roles.Has(RoleOwner)
+	if promoted {
+		if _, err := c.Update(ctx, UpdateGroupMemberRequest{
+			GroupID:  groupID,
+			MemberID: to.ID,
+			UserID:   to.UserID,
+			Roles:    append(RoleSet{RoleOwner}, to.Roles...),
+			State:    to.State,
+		}); err != nil {
+			return fmt.Errorf("failed to promote member %s: %w", toMemberID, err)
+		}
+	}
+
+	demoted := from.Roles.Remove(RoleOwner)
+	if len(demoted) == 0 {
+		// the previous owner stays in the group as a regular member
+		demoted = RoleSet{RoleMember}
+	}
+
+	_, err = c.Update(ctx, UpdateGroupMemberRequest{
+		GroupID:  groupID,
+		MemberID: from.ID,
+		UserID:   from.UserID,
+		Roles:    demoted,
+		State:    from.State,
+	})
+	if err == nil {
+		return nil
+	}
+	err = fmt.Errorf("failed to demote member %s: %w", fromMemberID, err)
+
+	if promoted {
+		if _, rollbackErr := c.Update(ctx, UpdateGroupMemberRequest{
+			GroupID:  groupID,
+			MemberID: to.ID,
+			UserID:   to.UserID,
+			Roles:    to.Roles,
+			State:    to.State,
+		}); rollbackErr != nil {
+			return &MultiError{errors: []error{err, fmt.Errorf("failed to roll back promotion of member %s: %w", toMemberID, rollbackErr)}}
+		}
+	}
+
+	return err
+}
@@ -0,0 +1,157 @@
+package rownd_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/rownd/client-go/pkg/rownd"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGroupOwnership(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		calls   []string
+		lists   int
+		failPut string
+		members map[string][]map[string]any
+	)
+	reset := func() {
+		calls = nil
+		lists = 0
+		failPut = ""
+		members = map[string][]map[string]any{
+			"group_1": {
+				{"id": "member_owner", "user_id": "user_owner", "roles": []any{"owner"}, "state": "active"},
+				{"id": "member_peer", "user_id": "user_peer", "roles": []any{"member"}, "state": "active"},
+				{"id": "member_pending", "user_id": "user_pending", "roles": []any{"member"}, "state": "pending"},
+			},
+		}
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/applications/", func(w http.ResponseWriter, r *http.Request) {
+		segments := pathSegments(r)
+		mu.Lock()
+		defer mu.Unlock()
+
+		if r.Method != http.MethodGet {
+			calls = append(calls, r.Method+" "+segments[5])
+		}
+
+		switch r.Method {
+		case http.MethodGet:
+			if len(segments) == 5 {
+				lists++
+				writeJSON(w, http.StatusOK, map[string]any{"results": members[segments[3]]})
+				return
+			}
+			writeMember(w, members, r)
+		case http.MethodPut:
+			if segments[5] == failPut {
+				writeJSON(w, http.StatusInternalServerError, map[string]any{"error": "boom"})
+				failPut = ""
+				return
+			}
+			var body map[string]any
+			json.NewDecoder(r.Body).Decode(&body)
+			applyMemberWrite(members, r, body)
+			writeJSON(w, http.StatusOK, body)
+		default:
+			applyMemberWrite(members, r, nil)
+			w.WriteHeader(http.StatusNoContent)
+		}
+	})
+	client := newTestClient(t, mux)
+	ctx := context.Background()
+
+	rolesOf := func(memberID string) []any {
+		for _, m := range members["group_1"] {
+			if m["id"] == memberID {
+				return m["roles"].([]any)
+			}
+		}
+		return nil
+	}
+
+	t.Run("guards", func(t *testing.T) {
+		reset()
+
+		err := client.GroupMembers.Delete(ctx, rownd.DeleteGroupMemberRequest{GroupID: "group_1", MemberID: "member_owner"})
+		assert.Equal(t, rownd.ErrLastOwner, rownd.KindOf(err))
+
+		_, err = client.GroupMembers.Update(ctx, rownd.UpdateGroupMemberRequest{
+			GroupID: "group_1", MemberID: "member_owner", Roles: rownd.RoleSet{"member"},
+		})
+		assert.Equal(t, rownd.ErrLastOwner, rownd.KindOf(err))
+
+		_, err = client.GroupMembers.Update(ctx, rownd.UpdateGroupMemberRequest{
+			GroupID: "group_1", MemberID: "member_owner", Roles: rownd.RoleSet{"owner"}, State: rownd.MemberStateSuspended,
+		})
+		assert.Equal(t, rownd.ErrLastOwner, rownd.KindOf(err), "suspending the last owner")
+		assert.Empty(t, calls)
+
+		err = client.GroupMembers.Delete(ctx, rownd.DeleteGroupMemberRequest{GroupID: "group_1", MemberID: "member_peer"})
+		assert.NoError(t, err)
+	})
+
+	t.Run("guards count only active owners", func(t *testing.T) {
+		reset()
+		members["group_1"] = append(members["group_1"],
+			map[string]any{"id": "member_invited", "user_id": "user_invited", "roles": []any{"owner"}, "state": "invite_pending"},
+		)
+
+		err := client.GroupMembers.Delete(ctx, rownd.DeleteGroupMemberRequest{GroupID: "group_1", MemberID: "member_owner"})
+		assert.Equal(t, rownd.ErrLastOwner, rownd.KindOf(err))
+		assert.Equal(t, 1, lists)
+
+		err = client.GroupMembers.Delete(ctx, rownd.DeleteGroupMemberRequest{GroupID: "group_1", MemberID: "member_invited"})
+		assert.NoError(t, err)
+		err = client.GroupMembers.Delete(ctx, rownd.DeleteGroupMemberRequest{GroupID: "group_1", MemberID: "member_peer"})
+		assert.NoError(t, err)
+		assert.Equal(t, 1, lists, "members that are not active owners are removed without listing the group")
+	})
+
+	t.Run("transfer", func(t *testing.T) {
+		reset()
+
+		err := client.GroupMembers.TransferOwnership(ctx, "group_1", "member_owner", "member_peer")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"PUT member_peer", "PUT member_owner"}, calls)
+		assert.Equal(t, []any{"owner", "member"}, rolesOf("member_peer"))
+		assert.Equal(t, []any{"member"}, rolesOf("member_owner"))
+	})
+
+	t.Run("transfer to member without state", func(t *testing.T) {
+		reset()
+		delete(members["group_1"][1], "state")
+
+		err := client.GroupMembers.TransferOwnership(ctx, "group_1", "member_owner", "member_peer")
+		assert.NoError(t, err, "members without a state are active")
+	})
+
+	t.Run("transfer rolls back", func(t *testing.T) {
+		reset()
+		failPut = "member_owner"
+
+		err := client.GroupMembers.TransferOwnership(ctx, "group_1", "member_owner", "member_peer")
+		assert.Error(t, err)
+		assert.Equal(t, []string{"PUT member_peer", "PUT member_owner", "PUT member_peer"}, calls)
+		assert.Equal(t, []any{"member"}, rolesOf("member_peer"))
+		assert.Equal(t, []any{"owner"}, rolesOf("member_owner"))
+	})
+
+	t.Run("transfer validation", func(t *testing.T) {
+		reset()
+
+		err := client.GroupMembers.TransferOwnership(ctx, "group_1", "member_peer", "member_owner")
+		assert.Equal(t, rownd.ErrValidation, rownd.KindOf(err))
+
+		err = client.GroupMembers.TransferOwnership(ctx, "group_1", "member_owner", "member_pending")
+		assert.Equal(t, rownd.ErrValidation, rownd.KindOf(err))
+		assert.Empty(t, calls)
+	})
+}
@@ -0,0 +1,333 @@
+package rownd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// DesiredMember is a member a group should have after reconciliation.
+type DesiredMember struct {
+	UserID string
+	Roles  RoleSet
+	// State is the member state to set. Empty leaves the state of existing members alone and lets
+	// the API pick it for new members.
+	State MemberState
+}
+
+// ReconcileOptions configures a membership reconciliation.
+type ReconcileOptions struct {
+	// DryRun plans the changes without applying them.
+	DryRun bool
+
+	// KeepUnlisted leaves members missing from the desired list in the group instead of removing
+	// them.
+	KeepUnlisted bool
+
+	// Concurrency is the number of changes applied at once. Defaults to 4.
+	Concurrency int
+}
+
+// MemberChangeAction ...
+type MemberChangeAction string
+
+const (
+	MemberChangeAdd    MemberChangeAction = "add"
+	MemberChangeUpdate MemberChangeAction = "update"
+	MemberChangeRemove MemberChangeAction = "remove"
+)
+
+// MemberChange is a change made, or planned, by a reconciliation.
+type MemberChange struct {
+	Action   MemberChangeAction `json:"action"`
+	UserID   string             `json:"user_id"`
+	MemberID string             `json:"member_id,omitempty"`
+
+	FromRoles RoleSet     `json:"from_roles,omitempty"`
+	ToRoles   RoleSet     `json:"to_roles,omitempty"`
+	FromState MemberState `json:"from_state,omitempty"`
+	ToState   MemberState `json:"to_state,omitempty"`
+
+	// Err is set when applying the change failed.
+	Err error `json:"-"`
+}
+
+// losesOwner reports whether the change takes away an active owner, by removing the member,
+// taking its owner role or making it inactive.
+func (m MemberChange) losesOwner() bool {
+	from := GroupMember{Roles: m.FromRoles, State: m.FromState}
+	switch m.Action {
+	case MemberChangeRemove:
+		return from.isActiveOwner()
+	case MemberChangeUpdate:
+		return from.isActiveOwner() && !m.makesActiveOwner()
+	}
+	return false
+}
+
+// makesActiveOwner reports whether the member is an active owner once the change is applied.
+func (m MemberChange) makesActiveOwner() bool {
+	return m.Action != MemberChangeRemove && GroupMember{Roles: m.ToRoles, State: m.ToState}.isActiveOwner()
+}
+
+// ReconcileReport describes the outcome of a reconciliation.
+type ReconcileReport struct {
+	GroupID   string         `json:"group_id"`
+	DryRun    bool           `json:"dry_run"`
+	Changes   []MemberChange `json:"changes"`
+	Unchanged int            `json:"unchanged"`
+	Succeeded int            `json:"succeeded"`
+	Failed    int            `json:"failed"`
+}
+
+// Reconcile makes the group's members match the desired list, matching members by user id. It
+// adds missing users, updates roles and states that differ and removes members that are not
+// listed. Changes that take away an active owner run last and one at a time, after every other
+// change, and only once an active owner is known to remain; otherwise they are skipped and
+// recorded as failed with an ErrLastOwner error.
+//
+// Failed changes are recorded in the report and do not stop the others; the returned error is
+// only set when the reconciliation could not be planned.
+func (c *groupMemberClient) Reconcile(ctx context.Context, groupID string, desired []DesiredMember, opts ReconcileOptions) (*ReconcileReport, error) {
+	var errs []error
+	if groupID == "" {
+		errs = append(errs, NewError(ErrValidation, "group id is required", nil))
+	}
+	if opts.Concurrency < 0 {
+		errs = append(errs, NewError(ErrValidation, "concurrency must not be negative", nil))
+	}
+	seen := map[string]bool{}
+	for _, d := range desired {
+		switch {
+		case d.UserID == "":
+			errs = append(errs, NewError(ErrValidation, "user id is required", nil))
+		case seen[d.UserID]:
+			errs = append(errs, NewError(ErrValidation, fmt.Sprintf("user %s is listed more than once", d.UserID), nil))
+		}
+		seen[d.UserID] = true
+		errs = append(errs, d.Roles.validate()...)
+		if d.State != "" && !d.State.validate() {
+			errs = append(errs, NewError(ErrValidation, fmt.Sprintf("invalid member state %q", d.State), nil))
+		}
+		if err := c.checkRoles(d.Roles); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return nil, &MultiError{errors: errs}
+	}
+
+	current := map[string]GroupMember{}
+	err := c.eachMember(ctx, groupID, func(member GroupMember) error {
+		current[member.UserID] = member
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	report := &ReconcileReport{GroupID: groupID, DryRun: opts.DryRun, Changes: []MemberChange{}}
+	report.Changes, report.Unchanged = planReconcile(current, desired, opts.KeepUnlisted)
+
+	// the first member of an empty group is made its owner by the API
+	if !ownerRemains(current, report.Changes, func(int) bool { return true }) && len(current) > 0 {
+		return report, NewError(ErrLastOwner, fmt.Sprintf("group %s would be left without an owner", groupID), nil)
+	}
+
+	if opts.DryRun {
+		return report, nil
+	}
+
+	concurrency := opts.Concurrency
+	if concurrency == 0 {
+		concurrency = defaultBulkConcurrency
+	}
+
+	var safe, losing []int
+	isLosing := map[int]bool{}
+	for i, change := range report.Changes {
+		if change.losesOwner() {
+			losing = append(losing, i)
+			isLosing[i] = true
+		} else {
+			safe = append(safe, i)
+		}
+	}
+
+	apply := func(i int) {
+		report.Changes[i].Err = c.applyMemberChange(ctx, groupID, report.Changes[i])
+	}
+	runConcurrently(safe, concurrency, apply)
+
+	// a failed change may have been the one granting the remaining owner
+	applied := func(i int) bool { return !isLosing[i] && report.Changes[i].Err == nil }
+	if len(losing) > 0 && !ownerRemains(current, report.Changes, applied) {
+		for _, i := range losing {
+			report.Changes[i].Err = NewError(ErrLastOwner, fmt.Sprintf("skipped: group %s would be left without an active owner", groupID), nil)
+		}
+	} else {
+		runConcurrently(losing, 1, apply)
+	}
+
+	for _, change := range report.Changes {
+		if change.Err != nil {
+			report.Failed++
+		} else {
+			report.Succeeded++
+		}
+	}
+
+	if err := ctx.Err(); err != nil {
+		return report, err
+	}
+
+	return report, nil
+}
+
+// ownerRemains reports whether the group keeps an active owner once the changes for which applied
+// returns true have been made and the changes that take away an owner have run. Members whose
+// other changes were not applied keep their current roles and state.
+func ownerRemains(current map[string]GroupMember, changes []MemberChange, applied func(int) bool) bool {
+	touched := map[string]bool{}
+	for i, change := range changes {
+		touched[change.UserID] = true
+		switch {
+		case applied(i):
+			if change.makesActiveOwner() {
+				return true
+			}
+		case !change.losesOwner():
+			if member, ok := current[change.UserID]; ok && member.isActiveOwner() {
+				return true
+			}
+		}
+	}
+	for userID, member := range current {
+		if !touched[userID] && member.isActiveOwner() {
+			return true
+		}
+	}
+	return false
+}
+
+func planReconcile(current map[string]GroupMember, desired []DesiredMember, keepUnlisted bool) ([]MemberChange, int) {
+	var (
+		changes   []MemberChange
+		unchanged int
+		listed    = map[string]bool{}
+	)
+
+	for _, d := range desired {
+		listed[d.UserID] = true
+
+		member, ok := current[d.UserID]
+		if !ok {
+			changes = append(changes, MemberChange{Action: MemberChangeAdd, UserID: d.UserID, ToRoles: d.Roles, ToState: d.State})
+			continue
+		}
+
+		state := d.State
+		if state == "" {
+			state = member.State
+		}
+		if sameRoles(member.Roles, d.Roles) && state == member.State {
+			unchanged++
+			continue
+		}
+		changes = append(changes, MemberChange{
+			Action:    MemberChangeUpdate,
+			UserID:    d.UserID,
+			MemberID:  member.ID,
+			FromRoles: member.Roles,
+			ToRoles:   d.Roles,
+			FromState: member.State,
+			ToState:   state,
+		})
+	}
+
+	if !keepUnlisted {
+		for userID, member := range current {
+			if listed[userID] {
+				continue
+			}
+			changes = append(changes, MemberChange{
+				Action:    MemberChangeRemove,
+				UserID:    userID,
+				MemberID:  member.ID,
+				FromRoles: member.Roles,
+				FromState: member.State,
+			})
+		}
+	}
+
+	sort.SliceStable(changes, func(i, j int) bool {
+		if changes[i].Action != changes[j].Action {
+			return changes[i].Action < changes[j].Action
+		}
+		return changes[i].UserID < changes[j].UserID
+	})
+
+	return changes, unchanged
+}
+
+func sameRoles(a, b RoleSet) bool {
+	a, b = RoleSet{}.Add(a...), RoleSet{}.Add(b...)
+	if len(a) != len(b) {
+		return false
+	}
+	for _, role := range a {
+		if !b.Has(role) {
+			return false
+		}
+	}
+	return true
+}
+
+// applyMemberChange applies a planned change. Updates and removals skip the per-call owner check,
+// which lists the whole group, because Reconcile has already checked that an owner remains.
+func (c *groupMemberClient) applyMemberChange(ctx context.Context, groupID string, change MemberChange) error {
+	switch change.Action {
+	case MemberChangeAdd:
+		_, err := c.Create(ctx, CreateGroupMemberRequest{
+			GroupID: groupID,
+			UserID:  change.UserID,
+			Roles:   change.ToRoles,
+			State:   change.ToState,
+		})
+		return err
+	case MemberChangeUpdate:
+		_, err := c.update(ctx, UpdateGroupMemberRequest{
+			GroupID:  groupID,
+			MemberID: change.MemberID,
+			UserID:   change.UserID,
+			Roles:    change.ToRoles,
+			State:    change.ToState,
+		})
+		return err
+	case MemberChangeRemove:
+		return c.remove(ctx, DeleteGroupMemberRequest{GroupID: groupID, MemberID: change.MemberID})
+	}
+
+	return fmt.Errorf("unknown member change %q", change.Action)
+}
+
+// runConcurrently calls fn for every item with at most n calls in flight.
+func runConcurrently(items []int, n int, fn func(int)) {
+	var (
+		wg  sync.WaitGroup
+		sem = make(chan struct{}, n)
+	)
+	for _, item := range items {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(item int) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			fn(item)
+		}(item)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,170 @@
+package rownd_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/rownd/client-go/pkg/rownd"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGroupReconcile(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		calls   []string
+		lists   int
+		failPut string
+		members map[string][]map[string]any
+	)
+	reset := func() {
+		calls = nil
+		lists = 0
+		failPut = ""
+		members = map[string][]map[string]any{
+			"group_1": {
+				{"id": "member_a", "user_id": "user_a", "roles": []any{"owner"}, "state": "active"},
+				{"id": "member_b", "user_id": "user_b", "roles": []any{"member"}, "state": "active"},
+				{"id": "member_c", "user_id": "user_c", "roles": []any{"member", "admin"}, "state": "active"},
+			},
+		}
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/applications/", func(w http.ResponseWriter, r *http.Request) {
+		segments := pathSegments(r)
+		mu.Lock()
+		defer mu.Unlock()
+
+		switch r.Method {
+		case http.MethodGet:
+			if len(segments) == 6 {
+				writeMember(w, members, r)
+				return
+			}
+			lists++
+			writeJSON(w, http.StatusOK, map[string]any{"results": members[segments[3]]})
+		case http.MethodPost:
+			var body map[string]any
+			json.NewDecoder(r.Body).Decode(&body)
+			calls = append(calls, "add "+body["user_id"].(string))
+			body["id"] = "member_" + body["user_id"].(string)[5:]
+			members[segments[3]] = append(members[segments[3]], body)
+			writeJSON(w, http.StatusOK, body)
+		case http.MethodPut:
+			var body map[string]any
+			json.NewDecoder(r.Body).Decode(&body)
+			calls = append(calls, "update "+segments[5])
+			if segments[5] == failPut {
+				writeJSON(w, http.StatusInternalServerError, map[string]any{"error": "boom"})
+				return
+			}
+			applyMemberWrite(members, r, body)
+			writeJSON(w, http.StatusOK, body)
+		case http.MethodDelete:
+			calls = append(calls, "remove "+segments[5])
+			applyMemberWrite(members, r, nil)
+			w.WriteHeader(http.StatusNoContent)
+		}
+	})
+	client := newTestClient(t, mux)
+	ctx := context.Background()
+
+	desired := []rownd.DesiredMember{
+		{UserID: "user_b", Roles: rownd.RoleSet{rownd.RoleOwner, rownd.RoleMember}},
+		{UserID: "user_c", Roles: rownd.RoleSet{"admin", "member"}},
+		{UserID: "user_d", Roles: rownd.RoleSet{rownd.RoleMember}},
+	}
+
+	t.Run("dry run", func(t *testing.T) {
+		reset()
+
+		report, err := client.GroupMembers.Reconcile(ctx, "group_1", desired, rownd.ReconcileOptions{DryRun: true})
+		assert.NoError(t, err)
+		assert.Empty(t, calls)
+		assert.Equal(t, 1, report.Unchanged, "role order does not matter")
+
+		var actions []string
+		for _, change := range report.Changes {
+			actions = append(actions, string(change.Action)+" "+change.UserID)
+		}
+		assert.Equal(t, []string{"add user_d", "remove user_a", "update user_b"}, actions)
+	})
+
+	t.Run("apply", func(t *testing.T) {
+		reset()
+
+		report, err := client.GroupMembers.Reconcile(ctx, "group_1", desired, rownd.ReconcileOptions{})
+		assert.NoError(t, err)
+		assert.Equal(t, 3, report.Succeeded)
+		assert.Zero(t, report.Failed)
+
+		// the previous owner is removed only after the new owner has been promoted
+		assert.Len(t, calls, 3)
+		assert.Equal(t, "remove member_a", calls[2])
+		assert.ElementsMatch(t, []string{"add user_d", "update member_b"}, calls[:2])
+		assert.Equal(t, 1, lists, "changes are applied without listing the group again")
+	})
+
+	t.Run("keeps the owner when the new owner is not granted", func(t *testing.T) {
+		reset()
+		failPut = "member_b"
+
+		report, err := client.GroupMembers.Reconcile(ctx, "group_1", desired, rownd.ReconcileOptions{})
+		assert.NoError(t, err)
+		assert.Equal(t, 2, report.Failed)
+		assert.NotContains(t, calls, "remove member_a")
+		for _, change := range report.Changes {
+			if change.UserID == "user_a" {
+				assert.Equal(t, rownd.ErrLastOwner, rownd.KindOf(change.Err))
+			}
+		}
+		assert.Len(t, members["group_1"], 4, "the previous owner is still a member")
+	})
+
+	t.Run("inactive owners do not count", func(t *testing.T) {
+		reset()
+
+		_, err := client.GroupMembers.Reconcile(ctx, "group_1", []rownd.DesiredMember{
+			{UserID: "user_b", Roles: rownd.RoleSet{rownd.RoleOwner}, State: rownd.MemberStateSuspended},
+		}, rownd.ReconcileOptions{})
+		assert.Equal(t, rownd.ErrLastOwner, rownd.KindOf(err))
+		assert.Empty(t, calls)
+	})
+
+	t.Run("keeps the state of invited members", func(t *testing.T) {
+		reset()
+		members["group_1"] = append(members["group_1"],
+			map[string]any{"id": "member_e", "user_id": "user_e", "roles": []any{"member"}, "state": "invite_pending"},
+		)
+
+		report, err := client.GroupMembers.Reconcile(ctx, "group_1", []rownd.DesiredMember{
+			{UserID: "user_e", Roles: rownd.RoleSet{"admin"}},
+		}, rownd.ReconcileOptions{KeepUnlisted: true})
+		assert.NoError(t, err)
+		assert.Equal(t, 1, report.Succeeded)
+		assert.NoError(t, report.Changes[0].Err)
+		assert.Equal(t, rownd.MemberStateInvitePending, report.Changes[0].ToState)
+		assert.Equal(t, []string{"update member_e"}, calls)
+	})
+
+	t.Run("refuses to remove every owner", func(t *testing.T) {
+		reset()
+
+		_, err := client.GroupMembers.Reconcile(ctx, "group_1", desired[1:], rownd.ReconcileOptions{})
+		assert.Equal(t, rownd.ErrLastOwner, rownd.KindOf(err))
+		assert.Empty(t, calls)
+
+		report, err := client.GroupMembers.Reconcile(ctx, "group_1", desired[1:], rownd.ReconcileOptions{KeepUnlisted: true})
+		assert.NoError(t, err)
+		assert.Equal(t, 1, report.Succeeded)
+		assert.Equal(t, []string{"add user_d"}, calls)
+	})
+
+	t.Run("validation", func(t *testing.T) {
+		_, err := client.GroupMembers.Reconcile(ctx, "group_1", []rownd.DesiredMember{{UserID: "user_a"}, {UserID: "user_a"}}, rownd.ReconcileOptions{})
+		assert.Equal(t, rownd.ErrValidation, rownd.KindOf(err))
+	})
+}
@@ -0,0 +1,615 @@
+package rownd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+const (
+	// GroupSnapshotVersion is the version of the snapshot format written by Snapshot.
+	GroupSnapshotVersion int = 1
+
+	defaultSnapshotLookupField = "email"
+
+	// groupMetaSnapshotIDKey records, in a restored group's metadata, the ID of the group it was
+	// restored from so that reruns update it instead of creating a copy.
+	groupMetaSnapshotIDKey = "snapshot_group_id"
+)
+
+// GroupSnapshot is a portable copy of an application's groups, members and pending invites.
+// It is plain JSON and can be stored as a backup or restored into another application.
+type GroupSnapshot struct {
+	Version   int       `json:"version"`
+	AppID     string    `json:"app_id"`
+	CreatedAt time.Time `json:"created_at"`
+
+	// LookupField is the profile field recorded for every member, used to find the same user
+	// in the application the snapshot is restored into.
+	LookupField string `json:"lookup_field"`
+
+	Groups []SnapshotGroup `json:"groups"`
+}
+
+// SnapshotGroup ...
+type SnapshotGroup struct {
+	ID              string           `json:"id"`
+	Name            string           `json:"name"`
+	AdmissionPolicy AdmissionPolicy  `json:"admission_policy"`
+	Meta            map[string]any   `json:"meta,omitempty"`
+	Members         []SnapshotMember `json:"members"`
+	Invites         []SnapshotInvite `json:"invites"`
+}
+
+// SnapshotMember ...
+type SnapshotMember struct {
+	UserID string `json:"user_id"`
+	// Lookup is the member's value of the snapshot's lookup field, empty if the user has none.
+	Lookup string      `json:"lookup,omitempty"`
+	Roles  RoleSet     `json:"roles"`
+	State  MemberState `json:"state,omitempty"`
+}
+
+// SnapshotInvite is a pending invite. Invites addressed to a user id also record the user's
+// lookup value.
+type SnapshotInvite struct {
+	Email        string  `json:"email,omitempty"`
+	Phone        int64   `json:"phone,omitempty"`
+	UserID       string  `json:"user_id,omitempty"`
+	UserLookup   string  `json:"user_lookup,omitempty"`
+	Roles        RoleSet `json:"roles"`
+	RedirectURL  string  `json:"redirect_url,omitempty"`
+	AppVariantID string  `json:"app_variant_id,omitempty"`
+}
+
+// SnapshotOption ...
+type SnapshotOption interface {
+	apply(*snapshotOptions)
+}
+
+type snapshotOptions struct {
+	lookupField string
+	groupIDs    []string
+}
+
+type snapshotLookupFieldOpt string
+
+func (o snapshotLookupFieldOpt) apply(opts *snapshotOptions) {
+	opts.lookupField = string(o)
+}
+
+// WithSnapshotLookupField sets the profile field recorded for each member. Defaults to "email".
+func WithSnapshotLookupField(field string) SnapshotOption {
+	return snapshotLookupFieldOpt(field)
+}
+
+type snapshotGroupsOpt []string
+
+func (o snapshotGroupsOpt) apply(opts *snapshotOptions) {
+	opts.groupIDs = o
+}
+
+// WithSnapshotGroups limits the snapshot to the given groups. All groups are included by default.
+func WithSnapshotGroups(groupIDs ...string) SnapshotOption {
+	return snapshotGroupsOpt(groupIDs)
+}
+
+// Snapshot captures the application's groups with their admission policies, metadata, members
+// and pending invites. Each member's value of the lookup field is fetched so the snapshot can be
+// restored into an application where the same people have different user ids.
+func (c *groupClient) Snapshot(ctx context.Context, opts ...SnapshotOption) (*GroupSnapshot, error) {
+	o := snapshotOptions{lookupField: defaultSnapshotLookupField}
+	for _, opt := range opts {
+		opt.apply(&o)
+	}
+	if strings.TrimSpace(o.lookupField) == "" {
+		return nil, &MultiError{errors: []error{NewError(ErrValidation, "lookup field is required", nil)}}
+	}
+
+	snapshot := &GroupSnapshot{
+		Version:     GroupSnapshotVersion,
+		AppID:       c.appID,
+		CreatedAt:   time.Now().UTC(),
+		LookupField: o.lookupField,
+		Groups:      []SnapshotGroup{},
+	}
+
+	lookups := map[string]string{}
+	lookup := func(userID string) (string, error) {
+		if value, ok := lookups[userID]; ok {
+			return value, nil
+		}
+		user, err := c.Users.Get(ctx, GetUserRequest{UserID: userID, Fields: []string{o.lookupField}})
+		if KindOf(err) == ErrNotFound {
+			lookups[userID] = ""
+			return "", nil
+		}
+		if err != nil {
+			return "", err
+		}
+		value := ""
+		if v, ok := user.Data[o.lookupField]; ok && v != nil {
+			value = fmt.Sprint(v)
+		}
+		lookups[userID] = value
+		return value, nil
+	}
+
+	add := func(group Group) error {
+		g := SnapshotGroup{
+			ID:              group.ID,
+			Name:            group.Name,
+			AdmissionPolicy: group.AdmissionPolicy,
+			Meta:            group.Meta,
+			Members:         []SnapshotMember{},
+			Invites:         []SnapshotInvite{},
+		}
+
+		err := c.GroupMembers.eachMember(ctx, group.ID, func(member GroupMember) error {
+			// members added by a pending invite are restored through the invite
+			if member.State == MemberStateInvitePending {
+				return nil
+			}
+			value, err := lookup(member.UserID)
+			if err != nil {
+				return err
+			}
+			g.Members = append(g.Members, SnapshotMember{UserID: member.UserID, Lookup: value, Roles: member.Roles, State: member.State})
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		err = c.GroupInvites.eachInvite(ctx, ListGroupInvitesRequest{GroupID: group.ID}, func(invite GroupInvite) error {
+			if invite.State != InviteStatePending {
+				return nil
+			}
+			i := SnapshotInvite{
+				Email:        invite.Email,
+				Phone:        invite.Phone,
+				UserID:       invite.UserID,
+				Roles:        invite.Roles,
+				RedirectURL:  invite.RedirectURL,
+				AppVariantID: invite.AppVariantID,
+			}
+			if invite.UserID != "" {
+				var err error
+				if i.UserLookup, err = lookup(invite.UserID); err != nil {
+					return err
+				}
+			}
+			g.Invites = append(g.Invites, i)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		snapshot.Groups = append(snapshot.Groups, g)
+		return nil
+	}
+
+	if len(o.groupIDs) > 0 {
+		for _, groupID := range o.groupIDs {
+			group, err := c.Get(ctx, GetGroupRequest{GroupID: groupID})
+			if err != nil {
+				return nil, err
+			}
+			if err := add(*group); err != nil {
+				return nil, err
+			}
+		}
+		return snapshot, nil
+	}
+
+	if err := c.eachGroup(ctx, add); err != nil {
+		return nil, err
+	}
+
+	return snapshot, nil
+}
+
+// RestoreOptions configures a snapshot restore.
+type RestoreOptions struct {
+	// DryRun plans the restore without making any changes.
+	DryRun bool
+
+	// AppVariantIDs maps app variant ids of the source application to those of the target.
+	// Unmapped ids are kept as they are.
+	AppVariantIDs map[string]string
+}
+
+// RestoreAction is what a restore did, or would do in a dry run, with a group.
+type RestoreAction string
+
+const (
+	RestoreActionCreate    RestoreAction = "create"
+	RestoreActionUpdate    RestoreAction = "update"
+	RestoreActionUnchanged RestoreAction = "unchanged"
+	RestoreActionFail      RestoreAction = "fail"
+)
+
+// RestoredGroup is the outcome for one group of the snapshot.
+type RestoredGroup struct {
+	SourceID string
+	// GroupID is the id of the group in the target application. It is empty in a dry run for
+	// groups that would be created.
+	GroupID string
+	Action  RestoreAction
+
+	Members        *ReconcileReport
+	InvitesCreated int
+	InvitesSkipped int
+
+	// Unresolved lists the lookup values, or source user ids when there is no lookup value, of
+	// members and invitees that did not match exactly one user in the target application.
+	Unresolved []string
+
+	Err error
+}
+
+// RestoreReport describes the outcome of Restore.
+type RestoreReport struct {
+	DryRun    bool
+	Groups    []RestoredGroup
+	Created   int
+	Updated   int
+	Unchanged int
+	Failed    int
+}
+
+func (s *GroupSnapshot) validate() error {
+	var errs []error
+
+	if s == nil {
+		return &MultiError{errors: []error{NewError(ErrValidation, "snapshot is required", nil)}}
+	}
+	if s.Version != GroupSnapshotVersion {
+		errs = append(errs, NewError(ErrValidation, fmt.Sprintf("unsupported snapshot version %d", s.Version), nil))
+	}
+	if strings.TrimSpace(s.LookupField) == "" {
+		errs = append(errs, NewError(ErrValidation, "snapshot lookup field is required", nil))
+	}
+	seen := map[string]bool{}
+	for _, g := range s.Groups {
+		switch {
+		case g.ID == "":
+			errs = append(errs, NewError(ErrValidation, "snapshot group id is required", nil))
+		case seen[g.ID]:
+			errs = append(errs, NewError(ErrValidation, fmt.Sprintf("group %s appears more than once", g.ID), nil))
+		}
+		seen[g.ID] = true
+		if strings.TrimSpace(g.Name) == "" {
+			errs = append(errs, NewError(ErrValidation, fmt.Sprintf("group %s has no name", g.ID), nil))
+		}
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+
+	return &MultiError{errors: errs}
+}
+
+// Restore recreates the snapshot's groups in the client's application. Groups restored before
+// are recognized by the source id recorded in their metadata, and when the snapshot was taken
+// from the same application its groups are matched by their own id; matched groups are updated
+// in place. Members are
+// added or updated but never removed, and invites are only created for invitees without a
+// pending invite, so running Restore again with the same snapshot changes nothing.
+//
+// Members and user-addressed invites are matched to users of the target application through the
+// snapshot's lookup field, or by their own id when a snapshot of the same application has no
+// lookup value for them; those that can't be matched are listed in Unresolved. Parent references
+// of nested groups are rewritten to the restored parents, and dropped when the parent is not part
+// of the snapshot. Failures are recorded per group; the returned error is only set when the
+// restore could not be planned.
+func (c *groupClient) Restore(ctx context.Context, snapshot *GroupSnapshot, opts RestoreOptions) (*RestoreReport, error) {
+	if err := snapshot.validate(); err != nil {
+		return nil, err
+	}
+
+	sameApp := snapshot.AppID == c.appID
+	existing := map[string]Group{}
+	err := c.eachGroup(ctx, func(group Group) error {
+		if sourceID, ok := group.Meta[groupMetaSnapshotIDKey].(string); ok && sourceID != "" {
+			if _, taken := existing[sourceID]; !taken {
+				existing[sourceID] = group
+			}
+		}
+		if sameApp {
+			// a group's own id wins over a copy restored from it
+			existing[group.ID] = group
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	r := &snapshotRestorer{
+		client:   c,
+		snapshot: snapshot,
+		opts:     opts,
+		sameApp:  sameApp,
+		existing: existing,
+		inSource: map[string]bool{},
+		restored: map[string]string{},
+		users:    map[string]string{},
+	}
+	for _, g := range snapshot.Groups {
+		r.inSource[g.ID] = true
+	}
+
+	report := &RestoreReport{DryRun: opts.DryRun}
+	for _, g := range orderSnapshotGroups(snapshot.Groups) {
+		result := r.restoreGroup(ctx, g)
+		switch result.Action {
+		case RestoreActionCreate:
+			report.Created++
+		case RestoreActionUpdate:
+			report.Updated++
+		case RestoreActionUnchanged:
+			report.Unchanged++
+		case RestoreActionFail:
+			report.Failed++
+		}
+		report.Groups = append(report.Groups, result)
+	}
+
+	if err := ctx.Err(); err != nil {
+		return report, err
+	}
+
+	return report, nil
+}
+
+type snapshotRestorer struct {
+	client   *groupClient
+	snapshot *GroupSnapshot
+	opts     RestoreOptions
+	sameApp  bool
+
+	// existing maps source group ids to groups restored by an earlier run or, for a snapshot of
+	// the same application, to the groups themselves.
+	existing map[string]Group
+	inSource map[string]bool
+	// restored maps source group ids to target ids once the group has been restored. Groups
+	// that would be created in a dry run map to "".
+	restored map[string]string
+	// users maps lookup values to target user ids, "" when they don't resolve.
+	users map[string]string
+}
+
+func (r *snapshotRestorer) restoreGroup(ctx context.Context, g SnapshotGroup) RestoredGroup {
+	result := RestoredGroup{SourceID: g.ID}
+	fail := func(err error) RestoredGroup {
+		result.Action = RestoreActionFail
+		result.Err = err
+		return result
+	}
+
+	current, ok := r.existing[g.ID]
+
+	meta := map[string]any{}
+	for k, v := range g.Meta {
+		meta[k] = v
+	}
+	if !ok || current.ID != g.ID {
+		meta[groupMetaSnapshotIDKey] = g.ID
+	}
+	if parent, ok := meta[groupMetaParentKey].(string); ok {
+		switch targetID, restored := r.restored[parent]; {
+		case !r.inSource[parent]:
+			delete(meta, groupMetaParentKey)
+		case !restored:
+			return fail(NewError(ErrValidation, fmt.Sprintf("parent group %s was not restored", parent), nil))
+		case targetID != "":
+			meta[groupMetaParentKey] = targetID
+		}
+	}
+
+	switch {
+	case !ok:
+		result.Action = RestoreActionCreate
+		if !r.opts.DryRun {
+			group, err := r.client.Create(ctx, CreateGroupRequest{Name: g.Name, AdmissionPolicy: g.AdmissionPolicy, Meta: meta})
+			if err != nil {
+				return fail(err)
+			}
+			result.GroupID = group.ID
+		}
+	case current.Name != g.Name || current.AdmissionPolicy != g.AdmissionPolicy || !sameJSON(current.Meta, meta):
+		result.Action = RestoreActionUpdate
+		result.GroupID = current.ID
+		if !r.opts.DryRun {
+			if _, err := r.client.Update(ctx, UpdateGroupRequest{GroupID: current.ID, Name: g.Name, AdmissionPolicy: g.AdmissionPolicy, Meta: meta}); err != nil {
+				return fail(err)
+			}
+		}
+	default:
+		result.Action = RestoreActionUnchanged
+		result.GroupID = current.ID
+	}
+	r.restored[g.ID] = result.GroupID
+
+	desired := []DesiredMember{}
+	seen := map[string]bool{}
+	for _, m := range g.Members {
+		userID, err := r.resolveUser(ctx, m.Lookup, m.UserID)
+		if err != nil {
+			return fail(err)
+		}
+		if userID == "" {
+			result.Unresolved = append(result.Unresolved, unresolvedName(m.Lookup, m.UserID))
+			continue
+		}
+		if seen[userID] {
+			continue
+		}
+		seen[userID] = true
+		desired = append(desired, DesiredMember{UserID: userID, Roles: m.Roles, State: m.State})
+	}
+
+	if result.GroupID == "" {
+		// the group doesn't exist yet in this dry run, so every member would be added
+		result.Members = &ReconcileReport{DryRun: true, Changes: []MemberChange{}}
+		for _, d := range desired {
+			result.Members.Changes = append(result.Members.Changes, MemberChange{Action: MemberChangeAdd, UserID: d.UserID, ToRoles: d.Roles, ToState: d.State})
+		}
+	} else {
+		members, err := r.client.GroupMembers.Reconcile(ctx, result.GroupID, desired, ReconcileOptions{DryRun: r.opts.DryRun, KeepUnlisted: true})
+		if err != nil {
+			return fail(err)
+		}
+		result.Members = members
+		if members.Failed > 0 {
+			result.Err = NewError(ErrAPI, fmt.Sprintf("%d member changes failed", members.Failed), nil)
+		}
+	}
+
+	pending := map[string]bool{}
+	if result.GroupID != "" {
+		err := r.client.GroupInvites.eachInvite(ctx, ListGroupInvitesRequest{GroupID: result.GroupID}, func(invite GroupInvite) error {
+			if invite.State != InviteStatePending {
+				return nil
+			}
+			for _, key := range inviteKeys(invite) {
+				pending[key] = true
+			}
+			return nil
+		})
+		if err != nil {
+			return fail(err)
+		}
+	}
+	for _, i := range g.Invites {
+		invitee := Invitee{Email: i.Email, Phone: i.Phone}
+		if i.UserID != "" {
+			userID, err := r.resolveUser(ctx, i.UserLookup, i.UserID)
+			if err != nil {
+				return fail(err)
+			}
+			if userID == "" {
+				result.Unresolved = append(result.Unresolved, unresolvedName(i.UserLookup, i.UserID))
+				continue
+			}
+			invitee = Invitee{UserID: userID}
+		}
+		if pending[invitee.key()] {
+			result.InvitesSkipped++
+			continue
+		}
+		pending[invitee.key()] = true
+
+		if !r.opts.DryRun {
+			appVariantID := i.AppVariantID
+			if mapped, ok := r.opts.AppVariantIDs[appVariantID]; ok {
+				appVariantID = mapped
+			}
+			_, err := r.client.GroupInvites.Create(ctx, CreateGroupInviteRequest{
+				GroupID:      result.GroupID,
+				UserID:       invitee.UserID,
+				Email:        invitee.Email,
+				Phone:        invitee.Phone,
+				Roles:        i.Roles,
+				RedirectURL:  i.RedirectURL,
+				AppVariantID: appVariantID,
+			})
+			if err != nil {
+				return fail(err)
+			}
+		}
+		result.InvitesCreated++
+	}
+
+	return result
+}
+
+// resolveUser finds the target user with the lookup value, returning "" when there isn't exactly
+// one. Without a lookup value, a snapshot of the same application falls back to the source user id.
+func (r *snapshotRestorer) resolveUser(ctx context.Context, lookup, sourceUserID string) (string, error) {
+	if lookup == "" {
+		if r.sameApp {
+			return sourceUserID, nil
+		}
+		return "", nil
+	}
+	if userID, ok := r.users[lookup]; ok {
+		return userID, nil
+	}
+
+	matches, err := r.client.Users.List(ctx, ListUsersRequest{
+		LookupFilter:      []string{lookup},
+		IncludeDuplicates: ToPointer(true),
+	})
+	if err != nil {
+		return "", err
+	}
+	userID := ""
+	if len(matches.Results) == 1 {
+		userID = matches.Results[0].GetID()
+	}
+	r.users[lookup] = userID
+
+	return userID, nil
+}
+
+func unresolvedName(lookup, userID string) string {
+	if lookup != "" {
+		return lookup
+	}
+	return "user " + userID
+}
+
+// orderSnapshotGroups puts parents before their children. Groups caught in a parent cycle keep
+// their original order at the end.
+func orderSnapshotGroups(groups []SnapshotGroup) []SnapshotGroup {
+	inSource := map[string]bool{}
+	for _, g := range groups {
+		inSource[g.ID] = true
+	}
+
+	ordered := make([]SnapshotGroup, 0, len(groups))
+	placed := map[string]bool{}
+	remaining := groups
+	for len(remaining) > 0 {
+		var next []SnapshotGroup
+		for _, g := range remaining {
+			parent, _ := g.Meta[groupMetaParentKey].(string)
+			if parent == "" || !inSource[parent] || placed[parent] {
+				ordered = append(ordered, g)
+				placed[g.ID] = true
+			} else {
+				next = append(next, g)
+			}
+		}
+		if len(next) == len(remaining) {
+			return append(ordered, next...)
+		}
+		remaining = next
+	}
+
+	return ordered
+}
+
+// sameJSON reports whether a and b encode to the same JSON value.
+func sameJSON(a, b any) bool {
+	var na, nb any
+	ab, err := json.Marshal(a)
+	if err != nil {
+		return false
+	}
+	bb, err := json.Marshal(b)
+	if err != nil {
+		return false
+	}
+	if json.Unmarshal(ab, &na) != nil || json.Unmarshal(bb, &nb) != nil {
+		return false
+	}
+	return reflect.DeepEqual(na, nb)
+}
@@ -0,0 +1,231 @@
+package rownd_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/rownd/client-go/pkg/rownd"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGroupSnapshotRestore(t *testing.T) {
+	source := http.NewServeMux()
+	source.HandleFunc("/hub/app-config", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]any{"app": map[string]any{"id": "app_source"}})
+	})
+	source.HandleFunc("/applications/", func(w http.ResponseWriter, r *http.Request) {
+		segments := pathSegments(r)
+		emails := map[string]string{"user_s1": "a@example.com", "user_s2": "b@example.com"}
+
+		switch {
+		case segments[2] == "users":
+			data := map[string]any{}
+			if email, ok := emails[segments[3]]; ok {
+				data["email"] = email
+			}
+			writeJSON(w, http.StatusOK, map[string]any{"data": data})
+		case len(segments) == 3:
+			writeJSON(w, http.StatusOK, map[string]any{"results": []map[string]any{
+				{"id": "org", "name": "Org", "admission_policy": "invite_only", "meta": map[string]any{"plan": "pro"}},
+				{"id": "team", "name": "Team", "admission_policy": "open", "meta": map[string]any{"parent_group_id": "org", "inherited_roles": []any{"owner"}}},
+			}})
+		case segments[4] == "members":
+			members := map[string][]map[string]any{
+				"org": {
+					{"id": "m1", "user_id": "user_s1", "roles": []any{"owner"}, "state": "active"},
+					{"id": "m2", "user_id": "user_s2", "roles": []any{"member"}, "state": "active"},
+					{"id": "m5", "user_id": "user_s1", "roles": []any{"member"}, "state": "invite_pending"},
+				},
+				"team": {
+					{"id": "m3", "user_id": "user_s2", "roles": []any{"owner"}, "state": "active"},
+					{"id": "m4", "user_id": "user_s3", "roles": []any{"member"}, "state": "active"},
+				},
+			}
+			writeJSON(w, http.StatusOK, map[string]any{"results": members[segments[3]]})
+		case segments[4] == "invites":
+			// a full page of accepted invites puts the pending ones on the second page
+			var accepted []map[string]any
+			for i := 0; i < 100; i++ {
+				accepted = append(accepted, map[string]any{"id": fmt.Sprintf("accepted_%d", i), "email": fmt.Sprintf("accepted_%d@example.com", i), "state": "accepted", "roles": []any{"member"}})
+			}
+			invites := map[string][]map[string]any{
+				"org": append(accepted, []map[string]any{
+					{"id": "i1", "email": "c@example.com", "state": "pending", "roles": []any{"member"}, "app_variant_id": "variant_s"},
+					{"id": "i2", "user_id": "user_s1", "state": "pending", "roles": []any{"member"}},
+					{"id": "i3", "email": "d@example.com", "state": "accepted", "roles": []any{"member"}},
+				}...),
+			}
+			writePage(w, r, invites[segments[3]])
+		}
+	})
+
+	var (
+		mu      sync.Mutex
+		writes  []string
+		groups  = map[string]map[string]any{}
+		members = map[string][]map[string]any{}
+		invites = map[string][]map[string]any{}
+	)
+	users := map[string]string{"a@example.com": "user_t1", "b@example.com": "user_t2"}
+
+	target := http.NewServeMux()
+	target.HandleFunc("/applications/", func(w http.ResponseWriter, r *http.Request) {
+		segments := pathSegments(r)
+		mu.Lock()
+		defer mu.Unlock()
+
+		var body map[string]any
+		if r.Method == http.MethodPost || r.Method == http.MethodPut {
+			json.NewDecoder(r.Body).Decode(&body)
+			writes = append(writes, r.Method+" "+r.URL.Path)
+		}
+
+		switch {
+		case segments[2] == "users" && len(segments) == 5:
+			data := map[string]any{}
+			for email, id := range users {
+				if id == segments[3] {
+					data["email"] = email
+				}
+			}
+			writeJSON(w, http.StatusOK, map[string]any{"data": data})
+		case segments[2] == "users":
+			assert.Equal(t, "true", r.URL.Query().Get("include_duplicates"), "duplicates must be listed to spot ambiguous lookups")
+			var results []map[string]any
+			if id, ok := users[r.URL.Query().Get("lookup_filter")]; ok {
+				results = append(results, map[string]any{"rownd_user": id})
+			}
+			writeJSON(w, http.StatusOK, map[string]any{"results": results})
+		case len(segments) == 3 && r.Method == http.MethodGet:
+			results := []map[string]any{}
+			for i := 1; i <= len(groups); i++ {
+				results = append(results, groups[fmt.Sprintf("group_%d", i)])
+			}
+			writeJSON(w, http.StatusOK, map[string]any{"results": results})
+		case len(segments) == 3:
+			body["id"] = fmt.Sprintf("group_%d", len(groups)+1)
+			groups[body["id"].(string)] = body
+			writeJSON(w, http.StatusOK, body)
+		case len(segments) == 4:
+			body["id"] = segments[3]
+			groups[segments[3]] = body
+			writeJSON(w, http.StatusOK, body)
+		case segments[4] == "members" && r.Method == http.MethodGet && len(segments) == 6:
+			writeMember(w, members, r)
+		case segments[4] == "members" && r.Method == http.MethodGet:
+			writeJSON(w, http.StatusOK, map[string]any{"results": members[segments[3]]})
+		case segments[4] == "members" && len(segments) == 5:
+			body["id"] = "member_" + body["user_id"].(string)
+			members[segments[3]] = append(members[segments[3]], body)
+			writeJSON(w, http.StatusOK, body)
+		case segments[4] == "members":
+			applyMemberWrite(members, r, body)
+			writeJSON(w, http.StatusOK, body)
+		case segments[4] == "invites" && r.Method == http.MethodGet:
+			writePage(w, r, invites[segments[3]])
+		case segments[4] == "invites":
+			body["id"] = fmt.Sprintf("invite_%d", len(invites[segments[3]])+1)
+			body["state"] = "pending"
+			invites[segments[3]] = append(invites[segments[3]], body)
+			writeJSON(w, http.StatusOK, map[string]any{"link": "https://example.com/invite", "invitation": body})
+		}
+	})
+
+	ctx := context.Background()
+	snapshot, err := newTestClient(t, source).Groups.Snapshot(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, rownd.GroupSnapshotVersion, snapshot.Version)
+	assert.Equal(t, "email", snapshot.LookupField)
+	assert.Equal(t, "app_source", snapshot.AppID)
+	assert.Len(t, snapshot.Groups, 2)
+	assert.Len(t, snapshot.Groups[0].Members, 2, "invite-pending members are covered by their invites")
+	assert.Equal(t, []rownd.SnapshotMember{
+		{UserID: "user_s2", Lookup: "b@example.com", Roles: rownd.RoleSet{rownd.RoleOwner}, State: rownd.MemberStateActive},
+		{UserID: "user_s3", Roles: rownd.RoleSet{rownd.RoleMember}, State: rownd.MemberStateActive},
+	}, snapshot.Groups[1].Members)
+	assert.Len(t, snapshot.Groups[0].Invites, 2, "only pending invites")
+	assert.Equal(t, "a@example.com", snapshot.Groups[0].Invites[1].UserLookup)
+
+	// snapshots travel as JSON
+	b, err := json.Marshal(snapshot)
+	assert.NoError(t, err)
+	var decoded rownd.GroupSnapshot
+	assert.NoError(t, json.Unmarshal(b, &decoded))
+
+	client := newTestClient(t, target)
+	opts := rownd.RestoreOptions{AppVariantIDs: map[string]string{"variant_s": "variant_t"}}
+
+	t.Run("dry run", func(t *testing.T) {
+		report, err := client.Groups.Restore(ctx, &decoded, rownd.RestoreOptions{DryRun: true})
+		assert.NoError(t, err)
+		assert.Empty(t, writes)
+		assert.Equal(t, 2, report.Created)
+		assert.Len(t, report.Groups[0].Members.Changes, 2)
+		assert.Equal(t, 2, report.Groups[0].InvitesCreated)
+		assert.Equal(t, []string{"user user_s3"}, report.Groups[1].Unresolved)
+	})
+
+	t.Run("restore", func(t *testing.T) {
+		report, err := client.Groups.Restore(ctx, &decoded, opts)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, report.Created)
+		assert.Equal(t, 0, report.Failed)
+		assert.Equal(t, "group_1", report.Groups[0].GroupID)
+
+		assert.Equal(t, "group_1", groups["group_2"]["meta"].(map[string]any)["parent_group_id"], "parent rewritten to the restored group")
+		assert.Equal(t, "org", groups["group_1"]["meta"].(map[string]any)["snapshot_group_id"])
+		assert.Len(t, members["group_1"], 2)
+		assert.Equal(t, "user_t2", members["group_2"][0]["user_id"])
+		assert.Len(t, invites["group_1"], 2)
+		assert.Equal(t, "variant_t", invites["group_1"][0]["app_variant_id"])
+		assert.Equal(t, "user_t1", invites["group_1"][1]["user_id"])
+	})
+
+	t.Run("rerun is a no-op", func(t *testing.T) {
+		writes = nil
+		// the pending invites are past the first page
+		var accepted []map[string]any
+		for i := 0; i < 100; i++ {
+			accepted = append(accepted, map[string]any{"id": fmt.Sprintf("accepted_%d", i), "email": fmt.Sprintf("accepted_%d@example.com", i), "state": "accepted"})
+		}
+		invites["group_1"] = append(accepted, invites["group_1"]...)
+
+		report, err := client.Groups.Restore(ctx, &decoded, opts)
+		assert.NoError(t, err)
+		assert.Empty(t, writes)
+		assert.Equal(t, 2, report.Unchanged)
+		assert.Equal(t, 2, report.Groups[0].InvitesSkipped)
+		assert.Equal(t, 2, report.Groups[0].Members.Unchanged)
+	})
+
+	t.Run("same application", func(t *testing.T) {
+		// user_t3 has no email, so only their id identifies them
+		members["group_2"] = append(members["group_2"], map[string]any{"id": "member_user_t3", "user_id": "user_t3", "roles": []any{"member"}, "state": "active"})
+
+		own, err := client.Groups.Snapshot(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, "", own.Groups[1].Members[1].Lookup)
+		writes = nil
+
+		report, err := client.Groups.Restore(ctx, own, rownd.RestoreOptions{})
+		assert.NoError(t, err)
+		assert.Empty(t, writes, "groups are matched by their own id")
+		assert.Equal(t, 2, report.Unchanged)
+		assert.Equal(t, "group_1", report.Groups[0].GroupID)
+		assert.Empty(t, report.Groups[0].Unresolved)
+		assert.Empty(t, report.Groups[1].Unresolved, "members without a lookup value keep their id")
+		assert.Equal(t, 2, report.Groups[1].Members.Unchanged)
+	})
+
+	t.Run("version", func(t *testing.T) {
+		future := decoded
+		future.Version = 2
+
+		_, err := client.Groups.Restore(ctx, &future, opts)
+		assert.Equal(t, rownd.ErrValidation, rownd.KindOf(err))
+	})
+}
@@ -0,0 +1,85 @@
+package rownd_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/rownd/client-go/pkg/rownd"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGroupUpdate(t *testing.T) {
+	var (
+		mu    sync.Mutex
+		group = map[string]any{
+			"id":               "group_1",
+			"name":             "Team",
+			"admission_policy": "invite_only",
+			"meta":             map[string]any{"plan": "free", "seats": 5, "legacy": true},
+		}
+		puts []map[string]any
+	)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/applications/app_test/groups/group_1", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if r.Method == http.MethodPut {
+			var body map[string]any
+			json.NewDecoder(r.Body).Decode(&body)
+			puts = append(puts, body)
+			for k, v := range body {
+				group[k] = v
+			}
+		}
+		writeJSON(w, http.StatusOK, group)
+	})
+	client := newTestClient(t, mux)
+	ctx := context.Background()
+
+	t.Run("validation", func(t *testing.T) {
+		_, err := client.Groups.Create(ctx, rownd.CreateGroupRequest{AdmissionPolicy: rownd.AdmissionPolicyOpen})
+		assert.Equal(t, rownd.ErrValidation, rownd.KindOf(err))
+
+		_, err = client.Groups.Update(ctx, rownd.UpdateGroupRequest{GroupID: "group_1", Name: " ", AdmissionPolicy: rownd.AdmissionPolicyOpen})
+		assert.Equal(t, rownd.ErrValidation, rownd.KindOf(err))
+	})
+
+	t.Run("get", func(t *testing.T) {
+		g, err := client.Groups.Get(ctx, rownd.GetGroupRequest{GroupID: "group_1"})
+		assert.NoError(t, err)
+		assert.Equal(t, "Team", g.Name)
+	})
+
+	t.Run("patch", func(t *testing.T) {
+		g, err := client.Groups.Patch(ctx, rownd.PatchGroupRequest{
+			GroupID:         "group_1",
+			AdmissionPolicy: rownd.ToPointer(rownd.AdmissionPolicyOpen),
+			Meta:            map[string]any{"plan": "pro", "legacy": nil},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, rownd.AdmissionPolicyOpen, g.AdmissionPolicy)
+		assert.Equal(t, []map[string]any{{
+			"name":             "Team",
+			"admission_policy": "open",
+			"meta":             map[string]any{"plan": "pro", "seats": 5.0},
+		}}, puts)
+
+		type teamMeta struct {
+			Plan  string `json:"plan"`
+			Seats int    `json:"seats"`
+		}
+		meta, err := rownd.DecodeGroupMeta[teamMeta](g)
+		assert.NoError(t, err)
+		assert.Equal(t, teamMeta{Plan: "pro", Seats: 5}, meta)
+
+		meta.Seats = 10
+		encoded, err := rownd.EncodeGroupMeta(meta)
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]any{"plan": "pro", "seats": 10.0}, encoded)
+	})
+}
@@ -0,0 +1,36 @@
+package rowndgrpc
+
+import (
+	"context"
+
+	"github.com/rownd/client-go/pkg/rownd"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// withToken forwards the token embedded in ctx as outgoing metadata. Calls without a token
+// are sent unchanged.
+func withToken(ctx context.Context) context.Context {
+	token := rownd.TokenFromCtx(ctx)
+	if token == nil || token.AccessToken == "" {
+		return ctx
+	}
+
+	return metadata.AppendToOutgoingContext(ctx, defaultMetadataKey, bearerPrefix+token.AccessToken)
+}
+
+// UnaryClientInterceptor returns a unary client interceptor that forwards the Rownd token
+// found in the call context.
+func UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return invoker(withToken(ctx), method, req, reply, cc, opts...)
+	}
+}
+
+// StreamClientInterceptor returns a stream client interceptor that forwards the Rownd token
+// found in the call context.
+func StreamClientInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return streamer(withToken(ctx), desc, cc, method, opts...)
+	}
+}
@@ -0,0 +1,129 @@
+package rowndgrpc_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/rownd/client-go/pkg/rownd"
+	rowndgrpc "github.com/rownd/client-go/pkg/rownd/grpc"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+type fakeValidator struct{}
+
+func (fakeValidator) Validate(_ context.Context, token string) (*rownd.Token, error) {
+	if token != "valid" {
+		return nil, rownd.NewError(rownd.ErrAuthentication, "invalid token", nil)
+	}
+	return &rownd.Token{UserID: "user_123", AccessToken: token}, nil
+}
+
+type fakeServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s fakeServerStream) Context() context.Context {
+	return s.ctx
+}
+
+func incoming(authorization string) context.Context {
+	return metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", authorization))
+}
+
+func TestServerInterceptors(t *testing.T) {
+	interceptor, err := rowndgrpc.NewInterceptor(fakeValidator{},
+		rowndgrpc.WithSkipMethods("/test.Service/Public"),
+		rowndgrpc.WithAuthorizer(func(_ context.Context, token *rownd.Token, fullMethod string) error {
+			if fullMethod == "/test.Service/Admin" {
+				return errors.New("admin only")
+			}
+			return nil
+		}),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create interceptor: %v", err)
+	}
+
+	unary := interceptor.Unary()
+	handler := func(ctx context.Context, req any) (any, error) {
+		token := rownd.TokenFromCtx(ctx)
+		if token == nil {
+			return "", nil
+		}
+		return token.UserID, nil
+	}
+
+	t.Run("valid token", func(t *testing.T) {
+		resp, err := unary(incoming("Bearer valid"), nil, &grpc.UnaryServerInfo{FullMethod: "/test.Service/Get"}, handler)
+		assert.NoError(t, err)
+		assert.Equal(t, "user_123", resp)
+	})
+
+	t.Run("invalid token", func(t *testing.T) {
+		_, err := unary(incoming("Bearer invalid"), nil, &grpc.UnaryServerInfo{FullMethod: "/test.Service/Get"}, handler)
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+
+	t.Run("missing metadata", func(t *testing.T) {
+		_, err := unary(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/test.Service/Get"}, handler)
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+
+	t.Run("permission denied", func(t *testing.T) {
+		_, err := unary(incoming("Bearer valid"), nil, &grpc.UnaryServerInfo{FullMethod: "/test.Service/Admin"}, handler)
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+
+	t.Run("skipped method", func(t *testing.T) {
+		resp, err := unary(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/test.Service/Public"}, handler)
+		assert.NoError(t, err)
+		assert.Equal(t, "", resp)
+	})
+
+	t.Run("stream", func(t *testing.T) {
+		stream := interceptor.Stream()
+		err := stream(nil, fakeServerStream{ctx: incoming("Bearer valid")}, &grpc.StreamServerInfo{FullMethod: "/test.Service/Watch"},
+			func(srv any, ss grpc.ServerStream) error {
+				token := rownd.TokenFromCtx(ss.Context())
+				assert.NotNil(t, token)
+				return nil
+			})
+		assert.NoError(t, err)
+
+		err = stream(nil, fakeServerStream{ctx: incoming("Bearer invalid")}, &grpc.StreamServerInfo{FullMethod: "/test.Service/Watch"},
+			func(srv any, ss grpc.ServerStream) error { return nil })
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+}
+
+func TestClientInterceptors(t *testing.T) {
+	ctx := rownd.AddTokenToCtx(context.Background(), &rownd.Token{AccessToken: "valid"})
+
+	t.Run("unary", func(t *testing.T) {
+		unary := rowndgrpc.UnaryClientInterceptor()
+		err := unary(ctx, "/test.Service/Get", nil, nil, nil,
+			func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+				md, _ := metadata.FromOutgoingContext(ctx)
+				assert.Equal(t, []string{"Bearer valid"}, md.Get("authorization"))
+				return nil
+			})
+		assert.NoError(t, err)
+	})
+
+	t.Run("stream", func(t *testing.T) {
+		stream := rowndgrpc.StreamClientInterceptor()
+		_, err := stream(ctx, &grpc.StreamDesc{}, nil, "/test.Service/Watch",
+			func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+				md, _ := metadata.FromOutgoingContext(ctx)
+				assert.Equal(t, []string{"Bearer valid"}, md.Get("authorization"))
+				return nil, nil
+			})
+		assert.NoError(t, err)
+	})
+}
@@ -0,0 +1,71 @@
+package rowndgrpc
+
+import (
+	"context"
+	"errors"
+
+	"github.com/rownd/client-go/pkg/rownd"
+)
+
+// Authorizer decides whether a validated token may call the supplied full method name.
+// Returning an error rejects the call with codes.PermissionDenied.
+type Authorizer func(ctx context.Context, token *rownd.Token, fullMethod string) error
+
+// InterceptorOption ...
+type InterceptorOption interface {
+	apply(*interceptorOptions)
+}
+
+type interceptorOptions struct {
+	metadataKey string
+	skipMethods map[string]struct{}
+	authorizer  Authorizer
+}
+
+func (o interceptorOptions) validate() error {
+	var errs []error
+
+	if o.metadataKey == "" {
+		errs = append(errs, errors.New("metadata key is required"))
+	}
+
+	return errors.Join(errs...)
+}
+
+type metadataKeyOpt string
+
+func (o metadataKeyOpt) apply(opts *interceptorOptions) {
+	opts.metadataKey = string(o)
+}
+
+// WithMetadataKey overrides the metadata key the bearer token is read from. Defaults to "authorization".
+func WithMetadataKey(key string) InterceptorOption {
+	return metadataKeyOpt(key)
+}
+
+type skipMethodsOpt []string
+
+func (o skipMethodsOpt) apply(opts *interceptorOptions) {
+	for _, method := range o {
+		opts.skipMethods[method] = struct{}{}
+	}
+}
+
+// WithSkipMethods disables authentication for the supplied full method names,
+// e.g. "/grpc.health.v1.Health/Check".
+func WithSkipMethods(methods ...string) InterceptorOption {
+	return skipMethodsOpt(methods)
+}
+
+type authorizerOpt struct {
+	fn Authorizer
+}
+
+func (o authorizerOpt) apply(opts *interceptorOptions) {
+	opts.authorizer = o.fn
+}
+
+// WithAuthorizer sets an authorization check that runs after the token has been validated.
+func WithAuthorizer(fn Authorizer) InterceptorOption {
+	return authorizerOpt{fn: fn}
+}
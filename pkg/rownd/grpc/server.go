@@ -0,0 +1,120 @@
+// Package rowndgrpc provides gRPC interceptors that authenticate calls with Rownd tokens.
+package rowndgrpc
+
+import (
+	"context"
+	"strings"
+
+	"github.com/rownd/client-go/pkg/rownd"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	defaultMetadataKey string = "authorization"
+	bearerPrefix       string = "Bearer "
+)
+
+// Interceptor validates Rownd tokens on incoming gRPC calls.
+type Interceptor struct {
+	validator   rownd.TokenValidator
+	metadataKey string
+	skipMethods map[string]struct{}
+	authorizer  Authorizer
+}
+
+// NewInterceptor creates a new server interceptor backed by the supplied validator.
+func NewInterceptor(validator rownd.TokenValidator, opts ...InterceptorOption) (*Interceptor, error) {
+	o := interceptorOptions{
+		metadataKey: defaultMetadataKey,
+		skipMethods: map[string]struct{}{},
+	}
+	for _, opt := range opts {
+		opt.apply(&o)
+	}
+	if err := o.validate(); err != nil {
+		return nil, err
+	}
+
+	i := &Interceptor{
+		validator:   validator,
+		metadataKey: o.metadataKey,
+		skipMethods: o.skipMethods,
+		authorizer:  o.authorizer,
+	}
+
+	return i, nil
+}
+
+// authenticate validates the token carried in the incoming metadata and returns a context
+// embedding it. Skipped methods are passed through untouched.
+func (i *Interceptor) authenticate(ctx context.Context, fullMethod string) (context.Context, error) {
+	if _, ok := i.skipMethods[fullMethod]; ok {
+		return ctx, nil
+	}
+
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "missing metadata")
+	}
+
+	values := md.Get(i.metadataKey)
+	if len(values) == 0 {
+		return nil, status.Error(codes.Unauthenticated, "missing token")
+	}
+
+	unverified, ok := strings.CutPrefix(values[0], bearerPrefix)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "invalid authorization scheme")
+	}
+
+	validated, err := i.validator.Validate(ctx, unverified)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "invalid token")
+	}
+
+	if i.authorizer != nil {
+		if err := i.authorizer(ctx, validated, fullMethod); err != nil {
+			return nil, status.Error(codes.PermissionDenied, err.Error())
+		}
+	}
+
+	return rownd.AddTokenToCtx(ctx, validated), nil
+}
+
+// Unary returns a unary server interceptor.
+func (i *Interceptor) Unary() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, err := i.authenticate(ctx, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// Stream returns a stream server interceptor.
+func (i *Interceptor) Stream() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := i.authenticate(ss.Context(), info.FullMethod)
+		if err != nil {
+			return err
+		}
+
+		return handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// serverStream overrides the stream context so handlers can read the validated token.
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+// Context implements grpc.ServerStream.Context().
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
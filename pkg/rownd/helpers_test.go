@@ -0,0 +1,112 @@
+package rownd_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/rownd/client-go/pkg/rownd"
+)
+
+const testAppID = "app_test"
+
+// newTestClient returns a client talking to a local server backed by mux. The app config
+// endpoint is served automatically unless mux already handles it.
+func newTestClient(t *testing.T, mux *http.ServeMux, opts ...rownd.ClientOption) *rownd.Client {
+	t.Helper()
+
+	if _, pattern := mux.Handler(httptest.NewRequest(http.MethodGet, "/hub/app-config", nil)); pattern != "/hub/app-config" {
+		mux.HandleFunc("/hub/app-config", func(w http.ResponseWriter, r *http.Request) {
+			writeJSON(w, http.StatusOK, map[string]any{"app": map[string]any{"id": testAppID}})
+		})
+	}
+
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	opts = append([]rownd.ClientOption{
+		rownd.WithAppKey("key"),
+		rownd.WithAppSecret("secret"),
+		rownd.WithBaseURL(srv.URL),
+	}, opts...)
+
+	client, err := rownd.NewClient(opts...)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	return client
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// pathSegments splits the request path, e.g. "/applications/app/users/u/data" becomes
+// ["applications", "app", "users", "u", "data"].
+func pathSegments(r *http.Request) []string {
+	return strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+}
+
+// applyMemberWrite keeps a fake group member store, keyed by group id, in sync with member
+// updates and removals so client-side owner checks see the current state.
+func applyMemberWrite(members map[string][]map[string]any, r *http.Request, body map[string]any) {
+	segments := pathSegments(r)
+	if len(segments) != 6 || segments[4] != "members" {
+		return
+	}
+
+	groupID, memberID := segments[3], segments[5]
+	for i, m := range members[groupID] {
+		if m["id"] != memberID {
+			continue
+		}
+		switch r.Method {
+		case http.MethodPut:
+			m["roles"] = body["roles"]
+		case http.MethodDelete:
+			members[groupID] = append(members[groupID][:i], members[groupID][i+1:]...)
+		}
+		return
+	}
+}
+
+// writeMember serves a single member from a fake group member store, as the member get endpoint
+// does.
+func writeMember(w http.ResponseWriter, members map[string][]map[string]any, r *http.Request) {
+	segments := pathSegments(r)
+	for _, m := range members[segments[3]] {
+		if m["id"] == segments[5] {
+			writeJSON(w, http.StatusOK, m)
+			return
+		}
+	}
+	writeJSON(w, http.StatusNotFound, map[string]any{"error": "not found"})
+}
+
+// writePage serves the page of results selected by the request's page_size and after
+// parameters, as the group list endpoints do. Like the API, it returns at most 100 results.
+func writePage(w http.ResponseWriter, r *http.Request, results []map[string]any) {
+	q := r.URL.Query()
+	pageSize, _ := strconv.Atoi(q.Get("page_size"))
+	if pageSize == 0 || pageSize > 100 {
+		pageSize = 100
+	}
+
+	start := 0
+	if after := q.Get("after"); after != "" {
+		for i, result := range results {
+			if result["id"] == after {
+				start = i + 1
+			}
+		}
+	}
+	end := min(start+pageSize, len(results))
+
+	writeJSON(w, http.StatusOK, map[string]any{"results": results[start:end]})
+}
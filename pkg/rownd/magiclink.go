@@ -3,7 +3,6 @@ package rownd
 import (
 	"context"
 	"fmt"
-	"net/http"
 )
 
 // VerificationType determines the method by which this magic link will be verified by the user.
@@ -135,13 +134,13 @@ func (c *magicLinkClient) Create(ctx context.Context, request CreateMagicLinkReq
 		return nil, err
 	}
 
-	endpoint, err := c.rowndURL("hub", "auth", "magic")
+	endpoint, err := c.endpoint(c.endpoints.MagicLinks.Create)
 	if err != nil {
 		return nil, err
 	}
 
 	var response *MagicLink
-	if err := c.request(ctx, http.MethodPost, endpoint.String(), request, &response, c.httpClientOpts...); err != nil {
+	if err := c.request(ctx, endpoint, request, &response); err != nil {
 		return nil, err
 	}
 
@@ -159,13 +158,13 @@ type MagicLinkOptions struct {
 
 // CreateMagicLink creates a new magic link
 func (c *magicLinkClient) CreateMagicLink(ctx context.Context, opts *MagicLinkOptions) (*MagicLink, error) {
-	endpoint, err := c.rowndURL("hub", "smart-links")
+	endpoint, err := c.endpoint(c.endpoints.MagicLinks.SmartLinks)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create endpoint: %w", err)
 	}
 
 	var response MagicLink
-	if err := c.request(ctx, http.MethodPost, endpoint.String(), opts, &response); err != nil {
+	if err := c.request(ctx, endpoint, opts, &response); err != nil {
 		return nil, err
 	}
 
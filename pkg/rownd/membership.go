@@ -0,0 +1,134 @@
+package rownd
+
+import (
+	"context"
+	"fmt"
+)
+
+// cachedMembers returns every member of the group, from the membership cache when it is enabled.
+func (c *groupMemberClient) cachedMembers(ctx context.Context, groupID string) ([]GroupMember, error) {
+	key := cacheKeyMembersPrefix + groupID
+	if c.membershipCacheDuration > 0 {
+		if cached, found := c.cache.Get(key); found {
+			if members, ok := cached.([]GroupMember); ok {
+				return append([]GroupMember(nil), members...), nil
+			}
+		}
+	}
+
+	var members []GroupMember
+	err := c.eachMember(ctx, groupID, func(member GroupMember) error {
+		members = append(members, member)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if c.membershipCacheDuration > 0 {
+		c.cache.Set(key, append([]GroupMember(nil), members...), c.membershipCacheDuration)
+	}
+
+	return members, nil
+}
+
+// invalidateMembers drops the cached member list of the group.
+func (c *Client) invalidateMembers(groupID string) {
+	c.cache.Delete(cacheKeyMembersPrefix + groupID)
+}
+
+// GetByUserID retrieves the member of a group that belongs to the user. It returns an
+// ErrNotFound error if the user is not a member.
+func (c *groupMemberClient) GetByUserID(ctx context.Context, groupID, userID string) (*GroupMember, error) {
+	var errs []error
+	if groupID == "" {
+		errs = append(errs, NewError(ErrValidation, "group id is required", nil))
+	}
+	if userID == "" {
+		errs = append(errs, NewError(ErrValidation, "user id is required", nil))
+	}
+	if len(errs) > 0 {
+		return nil, &MultiError{errors: errs}
+	}
+
+	members, err := c.cachedMembers(ctx, groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range members {
+		if members[i].UserID == userID {
+			return &members[i], nil
+		}
+	}
+
+	return nil, NewError(ErrNotFound, fmt.Sprintf("user %s is not a member of group %s", userID, groupID), nil)
+}
+
+// IsMember reports whether the user is a member of the group with all of the given roles. The
+// member's state is not checked; use GetByUserID to inspect it.
+func (c *groupMemberClient) IsMember(ctx context.Context, groupID, userID string, roles ...Role) (bool, error) {
+	member, err := c.GetByUserID(ctx, groupID, userID)
+	if KindOf(err) == ErrNotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	for _, role := range roles {
+		if !member.Roles.Has(role) {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// ListForUser returns the groups the user is a member of, with the user's membership in each.
+// The memberships included in the user's profile are used when present; otherwise every group is
+// checked, which takes a request per group unless the membership cache is enabled.
+func (c *groupClient) ListForUser(ctx context.Context, userID string) ([]UserGroupMembership, error) {
+	if userID == "" {
+		return nil, &MultiError{errors: []error{NewError(ErrValidation, "user id is required", nil)}}
+	}
+
+	user, err := c.Users.Get(ctx, GetUserRequest{UserID: userID})
+	if err != nil {
+		return nil, err
+	}
+	if len(user.Groups) > 0 {
+		return user.Groups, nil
+	}
+
+	var groups []Group
+	err = c.eachGroup(ctx, func(group Group) error {
+		groups = append(groups, group)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return c.membershipsIn(ctx, groups, userID)
+}
+
+// membershipsIn returns the user's membership in each of the groups they belong to, reading
+// member lists through the membership cache.
+func (c *groupClient) membershipsIn(ctx context.Context, groups []Group, userID string) ([]UserGroupMembership, error) {
+	memberships := []UserGroupMembership{}
+	for _, group := range groups {
+		members, err := c.GroupMembers.cachedMembers(ctx, group.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, member := range members {
+			if member.UserID == userID {
+				memberships = append(memberships, UserGroupMembership{Group: group, Member: member})
+				break
+			}
+		}
+	}
+
+	return memberships, nil
+}
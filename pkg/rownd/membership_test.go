@@ -0,0 +1,103 @@
+package rownd_test
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rownd/client-go/pkg/rownd"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMembershipLookups(t *testing.T) {
+	var (
+		mu          sync.Mutex
+		memberLists int
+		userGroups  []map[string]any
+	)
+	members := map[string][]map[string]any{
+		"group_1": {
+			{"id": "member_1", "user_id": "user_1", "roles": []any{"owner", "admin"}, "state": "active"},
+			{"id": "member_2", "user_id": "user_2", "roles": []any{"member"}, "state": "active"},
+		},
+		"group_2": {
+			{"id": "member_3", "user_id": "user_2", "roles": []any{"owner"}, "state": "active"},
+		},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/applications/", func(w http.ResponseWriter, r *http.Request) {
+		segments := pathSegments(r)
+		mu.Lock()
+		defer mu.Unlock()
+
+		switch {
+		case segments[2] == "users":
+			writeJSON(w, http.StatusOK, map[string]any{"data": map[string]any{}, "groups": userGroups})
+		case len(segments) == 3:
+			writeJSON(w, http.StatusOK, map[string]any{"results": []map[string]any{{"id": "group_1"}, {"id": "group_2"}}})
+		case len(segments) == 5 && r.Method == http.MethodGet:
+			memberLists++
+			writeJSON(w, http.StatusOK, map[string]any{"results": members[segments[3]]})
+		case r.Method == http.MethodGet:
+			writeMember(w, members, r)
+		default:
+			applyMemberWrite(members, r, nil)
+			w.WriteHeader(http.StatusNoContent)
+		}
+	})
+	client := newTestClient(t, mux, rownd.WithMembershipCache(time.Minute))
+	ctx := context.Background()
+
+	t.Run("get by user id", func(t *testing.T) {
+		member, err := client.GroupMembers.GetByUserID(ctx, "group_1", "user_2")
+		assert.NoError(t, err)
+		assert.Equal(t, "member_2", member.ID)
+
+		_, err = client.GroupMembers.GetByUserID(ctx, "group_1", "user_3")
+		assert.Equal(t, rownd.ErrNotFound, rownd.KindOf(err))
+	})
+
+	t.Run("is member", func(t *testing.T) {
+		ok, err := client.GroupMembers.IsMember(ctx, "group_1", "user_1", "owner", "admin")
+		assert.NoError(t, err)
+		assert.True(t, ok)
+
+		ok, err = client.GroupMembers.IsMember(ctx, "group_1", "user_2", "admin")
+		assert.NoError(t, err)
+		assert.False(t, ok)
+
+		ok, err = client.GroupMembers.IsMember(ctx, "group_1", "user_3")
+		assert.NoError(t, err)
+		assert.False(t, ok)
+	})
+
+	t.Run("cache", func(t *testing.T) {
+		assert.Equal(t, 1, memberLists, "lookups share the cached member list")
+
+		err := client.GroupMembers.Delete(ctx, rownd.DeleteGroupMemberRequest{GroupID: "group_1", MemberID: "member_2"})
+		assert.NoError(t, err)
+
+		ok, err := client.GroupMembers.IsMember(ctx, "group_1", "user_2")
+		assert.NoError(t, err)
+		assert.False(t, ok, "deleting a member invalidates the cache")
+	})
+
+	t.Run("list for user", func(t *testing.T) {
+		memberships, err := client.Groups.ListForUser(ctx, "user_1")
+		assert.NoError(t, err)
+		if assert.Len(t, memberships, 1) {
+			assert.Equal(t, "group_1", memberships[0].Group.ID)
+			assert.Equal(t, "member_1", memberships[0].Member.ID)
+		}
+
+		userGroups = []map[string]any{{"group": map[string]any{"id": "group_9"}, "member": map[string]any{"id": "member_9"}}}
+		memberships, err = client.Groups.ListForUser(ctx, "user_1")
+		assert.NoError(t, err)
+		if assert.Len(t, memberships, 1) {
+			assert.Equal(t, "group_9", memberships[0].Group.ID, "profile memberships are used when present")
+		}
+	})
+}
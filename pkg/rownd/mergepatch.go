@@ -0,0 +1,95 @@
+package rownd
+
+import "reflect"
+
+// MergePatch computes the profile data patch that turns from into to. Profile patches are
+// top-level only: changed fields, including nested objects, are sent in full and replace the
+// stored value whole, and removed fields are set to nil. It returns an empty map when nothing
+// changed.
+func MergePatch(from, to map[string]any) map[string]any {
+	patch := map[string]any{}
+
+	for field, value := range to {
+		old, ok := from[field]
+		if !ok {
+			patch[field] = value
+			continue
+		}
+
+		if !reflect.DeepEqual(old, value) {
+			patch[field] = value
+		}
+	}
+
+	for field := range from {
+		if _, ok := to[field]; !ok {
+			patch[field] = nil
+		}
+	}
+
+	return patch
+}
+
+// ApplyMergePatch applies a JSON Merge Patch (RFC 7386) to target and returns the result. It is
+// recursive: nested objects are merged field by field rather than replaced, and a nil value
+// removes the field at any depth. target is not modified.
+func ApplyMergePatch(target, patch map[string]any) map[string]any {
+	result := make(map[string]any, len(target))
+	for field, value := range target {
+		result[field] = value
+	}
+
+	for field, value := range patch {
+		if value == nil {
+			delete(result, field)
+			continue
+		}
+
+		nested, ok := value.(map[string]any)
+		if !ok {
+			result[field] = value
+			continue
+		}
+
+		existing, _ := result[field].(map[string]any)
+		result[field] = ApplyMergePatch(existing, nested)
+	}
+
+	return result
+}
+
+// DiffUsers returns a request that patches from into to, sending only the changed profile data.
+// The user id is taken from to, falling back to from.
+func DiffUsers(from, to *User) PatchUserRequest {
+	userID := to.GetID()
+	if userID == "" {
+		userID = from.GetID()
+	}
+
+	return PatchUserRequest{
+		UserID: userID,
+		Data:   MergePatch(from.Data, to.Data),
+	}
+}
+
+// Set sets a profile field in the patch.
+func (r *PatchUserRequest) Set(field string, value any) *PatchUserRequest {
+	if r.Data == nil {
+		r.Data = map[string]any{}
+	}
+	r.Data[field] = value
+
+	return r
+}
+
+// Unset removes profile fields by sending them as explicit nulls.
+func (r *PatchUserRequest) Unset(fields ...string) *PatchUserRequest {
+	if r.Data == nil {
+		r.Data = map[string]any{}
+	}
+	for _, field := range fields {
+		r.Data[field] = nil
+	}
+
+	return r
+}
@@ -0,0 +1,66 @@
+package rownd_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/rownd/client-go/pkg/rownd"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMergePatch(t *testing.T) {
+	from := map[string]any{
+		"first_name": "Jane",
+		"legacy":     "x",
+		"tags":       []any{"a", "b"},
+		"address":    map[string]any{"city": "Denver", "zip": "80202", "unit": "4"},
+	}
+	to := map[string]any{
+		"first_name": "Jane",
+		"last_name":  "Doe",
+		"tags":       []any{"a"},
+		"address":    map[string]any{"city": "Boulder", "zip": "80202"},
+	}
+
+	patch := rownd.MergePatch(from, to)
+	assert.Equal(t, map[string]any{
+		"last_name": "Doe",
+		"legacy":    nil,
+		"tags":      []any{"a"},
+		"address":   map[string]any{"city": "Boulder", "zip": "80202"},
+	}, patch, "nested objects are sent whole")
+
+	assert.Empty(t, rownd.MergePatch(to, to))
+}
+
+func TestApplyMergePatch(t *testing.T) {
+	target := map[string]any{
+		"legacy":  "x",
+		"address": map[string]any{"city": "Denver", "zip": "80202", "unit": "4"},
+	}
+
+	result := rownd.ApplyMergePatch(target, map[string]any{
+		"legacy":  nil,
+		"tier":    "gold",
+		"address": map[string]any{"city": "Boulder", "unit": nil},
+	})
+	assert.Equal(t, map[string]any{
+		"tier":    "gold",
+		"address": map[string]any{"city": "Boulder", "zip": "80202"},
+	}, result)
+	assert.Equal(t, "x", target["legacy"], "ApplyMergePatch must not modify its input")
+}
+
+func TestDiffUsersAndUnset(t *testing.T) {
+	before := &rownd.User{ID: "user_1", Data: map[string]any{"email": "a@example.com", "phone": "+1555"}}
+	after := &rownd.User{Data: map[string]any{"email": "b@example.com", "phone": "+1555"}}
+
+	request := rownd.DiffUsers(before, after)
+	assert.Equal(t, "user_1", request.UserID)
+	assert.Equal(t, map[string]any{"email": "b@example.com"}, request.Data)
+
+	request.Unset("phone", "nickname").Set("first_name", "Jane")
+	body, err := json.Marshal(request)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"data":{"email":"b@example.com","phone":null,"nickname":null,"first_name":"Jane"}}`, string(body))
+}
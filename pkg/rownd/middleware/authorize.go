@@ -0,0 +1,58 @@
+package rowndmiddleware
+
+import (
+	"net/http"
+
+	"github.com/rownd/client-go/pkg/rownd"
+)
+
+// GroupIDFunc returns the ID of the group a request acts on, for example from a path parameter.
+type GroupIDFunc func(r *http.Request) string
+
+// RequireGroupRoles returns a middleware that only lets through users with all of the given roles
+// in the request's group, held directly or inherited through the group hierarchy. With no roles
+// any role in the group is enough. Place it after WithAuthentication; requests without a token
+// are rejected with 401 and requests without the roles, or for an unknown group, with 403.
+//
+// Resolving the roles reads every group above the request's group and their member lists. Enable
+// rownd.WithMembershipCache on the client so that requests are served from memory.
+func RequireGroupRoles(resolver rownd.RoleResolver, groupID GroupIDFunc, roles ...rownd.Role) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token := rownd.TokenFromCtx(r.Context())
+			if token == nil || token.UserID == "" {
+				http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+				return
+			}
+
+			id := groupID(r)
+			if id == "" {
+				http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+				return
+			}
+
+			effective, err := resolver.EffectiveRoles(r.Context(), id, token.UserID)
+			if rownd.KindOf(err) == rownd.ErrNotFound {
+				http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+				return
+			}
+			if err != nil {
+				http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+				return
+			}
+
+			allowed := len(effective) > 0
+			for _, role := range roles {
+				if !effective.Has(role) {
+					allowed = false
+				}
+			}
+			if !allowed {
+				http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
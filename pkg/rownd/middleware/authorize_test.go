@@ -0,0 +1,55 @@
+package rowndmiddleware_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/rownd/client-go/pkg/rownd"
+	rowndmiddleware "github.com/rownd/client-go/pkg/rownd/middleware"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeRoleResolver map[string]rownd.RoleSet
+
+func (f fakeRoleResolver) EffectiveRoles(ctx context.Context, groupID, userID string) (rownd.RoleSet, error) {
+	if groupID == "broken" {
+		return nil, errors.New("boom")
+	}
+	if groupID == "missing" {
+		return nil, rownd.NewError(rownd.ErrNotFound, "group not found", nil)
+	}
+	return f[groupID+"/"+userID], nil
+}
+
+func TestRequireGroupRoles(t *testing.T) {
+	resolver := fakeRoleResolver{
+		"team_1/user_1": {rownd.RoleOwner, rownd.RoleMember},
+		"team_1/user_2": {rownd.RoleMember},
+	}
+	groupID := func(r *http.Request) string { return r.URL.Query().Get("group") }
+
+	do := func(mw func(http.Handler) http.Handler, userID, group string) int {
+		req := httptest.NewRequest(http.MethodGet, "/?group="+group, nil)
+		if userID != "" {
+			req = req.WithContext(rownd.AddTokenToCtx(req.Context(), &rownd.Token{UserID: userID}))
+		}
+		rec := httptest.NewRecorder()
+		mw(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})).ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	owners := rowndmiddleware.RequireGroupRoles(resolver, groupID, rownd.RoleOwner)
+	assert.Equal(t, http.StatusOK, do(owners, "user_1", "team_1"))
+	assert.Equal(t, http.StatusForbidden, do(owners, "user_2", "team_1"))
+	assert.Equal(t, http.StatusUnauthorized, do(owners, "", "team_1"))
+	assert.Equal(t, http.StatusForbidden, do(owners, "user_1", ""))
+	assert.Equal(t, http.StatusForbidden, do(owners, "user_1", "missing"))
+	assert.Equal(t, http.StatusInternalServerError, do(owners, "user_1", "broken"))
+
+	anyRole := rowndmiddleware.RequireGroupRoles(resolver, groupID)
+	assert.Equal(t, http.StatusOK, do(anyRole, "user_2", "team_1"))
+	assert.Equal(t, http.StatusForbidden, do(anyRole, "user_3", "team_1"))
+}
@@ -0,0 +1,30 @@
+// Package rowndchi provides Rownd authentication middleware for the chi router.
+//
+// chi middlewares share the net/http signature, so the adapter delegates to rowndmiddleware
+// and exposes a context accessor for symmetry with the other framework adapters.
+package rowndchi
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rownd/client-go/pkg/rownd"
+	rowndmiddleware "github.com/rownd/client-go/pkg/rownd/middleware"
+)
+
+// WithAuthentication returns a chi middleware that validates the request token using the
+// supplied handler configuration and embeds the validated token in the request context.
+func WithAuthentication(handler rowndmiddleware.Handler) func(next http.Handler) http.Handler {
+	return rowndmiddleware.WithAuthentication(handler)
+}
+
+// Use registers the authentication middleware on the supplied router.
+func Use(r chi.Router, handler rowndmiddleware.Handler) {
+	r.Use(WithAuthentication(handler))
+}
+
+// TokenFromContext extracts the validated token from the request context.
+func TokenFromContext(ctx context.Context) *rownd.Token {
+	return rownd.TokenFromCtx(ctx)
+}
@@ -0,0 +1,68 @@
+package rowndchi_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rownd/client-go/pkg/rownd"
+	rowndmiddleware "github.com/rownd/client-go/pkg/rownd/middleware"
+	rowndchi "github.com/rownd/client-go/pkg/rownd/middleware/chi"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeValidator struct{}
+
+func (fakeValidator) Validate(_ context.Context, token string) (*rownd.Token, error) {
+	if token != "valid" {
+		return nil, rownd.NewError(rownd.ErrAuthentication, "invalid token", nil)
+	}
+	return &rownd.Token{UserID: "user_123", AccessToken: token}, nil
+}
+
+func TestWithAuthentication(t *testing.T) {
+	handler, err := rowndmiddleware.NewHandler(fakeValidator{})
+	if err != nil {
+		t.Fatalf("Failed to create handler: %v", err)
+	}
+
+	router := chi.NewRouter()
+	rowndchi.Use(router, *handler)
+	router.Get("/me", func(w http.ResponseWriter, r *http.Request) {
+		token := rowndchi.TokenFromContext(r.Context())
+		assert.NotNil(t, token)
+		w.Write([]byte(token.UserID))
+	})
+
+	t.Run("valid token", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/me", nil)
+		req.Header.Set("Authentication", "Bearer valid")
+		rec := httptest.NewRecorder()
+
+		router.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "user_123", rec.Body.String())
+	})
+
+	t.Run("invalid token", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/me", nil)
+		req.Header.Set("Authentication", "Bearer invalid")
+		rec := httptest.NewRecorder()
+
+		router.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+
+	t.Run("missing token", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/me", nil)
+		rec := httptest.NewRecorder()
+
+		router.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+}
@@ -0,0 +1,19 @@
+module github.com/rownd/client-go/pkg/rownd/middleware/chi
+
+go 1.21
+
+require (
+	github.com/go-chi/chi/v5 v5.0.12
+	github.com/rownd/client-go v0.0.0-00010101000000-000000000000
+	github.com/stretchr/testify v1.10.0
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/golang-jwt/jwt/v5 v5.2.1 // indirect
+	github.com/patrickmn/go-cache v2.1.0+incompatible // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+replace github.com/rownd/client-go => ../../../..
@@ -0,0 +1,45 @@
+// Package rowndecho provides Rownd authentication middleware for the echo web framework.
+package rowndecho
+
+import (
+	"errors"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rownd/client-go/pkg/rownd"
+	rowndmiddleware "github.com/rownd/client-go/pkg/rownd/middleware"
+)
+
+// ContextKey is the key under which the validated token is stored in the echo context.
+const ContextKey = "rownd_token"
+
+// WithAuthentication returns an echo middleware that validates the request token using the
+// supplied handler configuration. The validated token is stored in the echo context and in
+// the request context so both TokenFromContext and rownd.TokenFromCtx can retrieve it.
+func WithAuthentication(handler rowndmiddleware.Handler) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+
+			validated, err := handler.Authenticate(req)
+			if err != nil {
+				handler.ErrorHandler(c.Response(), req, errors.New("Forbidden"))
+				return nil
+			}
+
+			c.Set(ContextKey, validated)
+			c.SetRequest(req.WithContext(rownd.AddTokenToCtx(req.Context(), validated)))
+
+			return next(c)
+		}
+	}
+}
+
+// TokenFromContext extracts the validated token from the echo context.
+func TokenFromContext(c echo.Context) *rownd.Token {
+	t, ok := c.Get(ContextKey).(*rownd.Token)
+	if !ok {
+		return nil
+	}
+
+	return t
+}
@@ -0,0 +1,50 @@
+// Package rowndfiber provides Rownd authentication middleware for the fiber web framework.
+package rowndfiber
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
+	"github.com/rownd/client-go/pkg/rownd"
+	rowndmiddleware "github.com/rownd/client-go/pkg/rownd/middleware"
+)
+
+// LocalsKey is the key under which the validated token is stored in the fiber locals.
+const LocalsKey = "rownd_token"
+
+// WithAuthentication returns a fiber middleware that validates the request token using the
+// supplied handler configuration. Since the handler works with net/http types, the fasthttp
+// request is converted before extraction and the error handler is run through fiber's adaptor.
+// The validated token is stored in the fiber locals and in the user context.
+func WithAuthentication(handler rowndmiddleware.Handler) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		req, err := adaptor.ConvertRequest(c, false)
+		if err != nil {
+			return err
+		}
+
+		validated, err := handler.Authenticate(req.WithContext(c.UserContext()))
+		if err != nil {
+			return adaptor.HTTPHandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				handler.ErrorHandler(w, r, errors.New("Forbidden"))
+			})(c)
+		}
+
+		c.Locals(LocalsKey, validated)
+		c.SetUserContext(rownd.AddTokenToCtx(c.UserContext(), validated))
+
+		return c.Next()
+	}
+}
+
+// TokenFromContext extracts the validated token from the fiber locals.
+func TokenFromContext(c *fiber.Ctx) *rownd.Token {
+	t, ok := c.Locals(LocalsKey).(*rownd.Token)
+	if !ok {
+		return nil
+	}
+
+	return t
+}
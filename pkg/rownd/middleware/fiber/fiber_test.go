@@ -0,0 +1,70 @@
+package rowndfiber_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rownd/client-go/pkg/rownd"
+	rowndmiddleware "github.com/rownd/client-go/pkg/rownd/middleware"
+	rowndfiber "github.com/rownd/client-go/pkg/rownd/middleware/fiber"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeValidator struct{}
+
+func (fakeValidator) Validate(_ context.Context, token string) (*rownd.Token, error) {
+	if token != "valid" {
+		return nil, rownd.NewError(rownd.ErrAuthentication, "invalid token", nil)
+	}
+	return &rownd.Token{UserID: "user_123", AccessToken: token}, nil
+}
+
+func TestWithAuthentication(t *testing.T) {
+	handler, err := rowndmiddleware.NewHandler(fakeValidator{})
+	if err != nil {
+		t.Fatalf("Failed to create handler: %v", err)
+	}
+
+	app := fiber.New()
+	app.Use(rowndfiber.WithAuthentication(*handler))
+	app.Get("/me", func(c *fiber.Ctx) error {
+		token := rowndfiber.TokenFromContext(c)
+		assert.NotNil(t, token)
+		assert.Equal(t, token, rownd.TokenFromCtx(c.UserContext()))
+		return c.SendString(token.UserID)
+	})
+
+	t.Run("valid token", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/me", nil)
+		req.Header.Set("Authentication", "Bearer valid")
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		body, err := io.ReadAll(resp.Body)
+		assert.NoError(t, err)
+		assert.Equal(t, "user_123", string(body))
+	})
+
+	t.Run("invalid token", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/me", nil)
+		req.Header.Set("Authentication", "Bearer invalid")
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	})
+
+	t.Run("missing token", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/me", nil)
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	})
+}
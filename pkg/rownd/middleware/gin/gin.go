@@ -0,0 +1,46 @@
+// Package rowndgin provides Rownd authentication middleware for the gin web framework.
+package rowndgin
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rownd/client-go/pkg/rownd"
+	rowndmiddleware "github.com/rownd/client-go/pkg/rownd/middleware"
+)
+
+// ContextKey is the key under which the validated token is stored in the gin context.
+const ContextKey = "rownd_token"
+
+// WithAuthentication returns a gin middleware that validates the request token using the
+// supplied handler configuration. The validated token is stored in the gin context and in
+// the request context so both TokenFromContext and rownd.TokenFromCtx can retrieve it.
+func WithAuthentication(handler rowndmiddleware.Handler) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		validated, err := handler.Authenticate(c.Request)
+		if err != nil {
+			handler.ErrorHandler(c.Writer, c.Request, errors.New("Forbidden"))
+			c.Abort()
+			return
+		}
+
+		c.Set(ContextKey, validated)
+		c.Request = c.Request.WithContext(rownd.AddTokenToCtx(c.Request.Context(), validated))
+		c.Next()
+	}
+}
+
+// TokenFromContext extracts the validated token from the gin context.
+func TokenFromContext(c *gin.Context) *rownd.Token {
+	v, ok := c.Get(ContextKey)
+	if !ok {
+		return nil
+	}
+
+	t, ok := v.(*rownd.Token)
+	if !ok {
+		return nil
+	}
+
+	return t
+}
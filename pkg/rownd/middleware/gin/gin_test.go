@@ -0,0 +1,71 @@
+package rowndgin_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rownd/client-go/pkg/rownd"
+	rowndmiddleware "github.com/rownd/client-go/pkg/rownd/middleware"
+	rowndgin "github.com/rownd/client-go/pkg/rownd/middleware/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeValidator struct{}
+
+func (fakeValidator) Validate(_ context.Context, token string) (*rownd.Token, error) {
+	if token != "valid" {
+		return nil, rownd.NewError(rownd.ErrAuthentication, "invalid token", nil)
+	}
+	return &rownd.Token{UserID: "user_123", AccessToken: token}, nil
+}
+
+func TestWithAuthentication(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handler, err := rowndmiddleware.NewHandler(fakeValidator{})
+	if err != nil {
+		t.Fatalf("Failed to create handler: %v", err)
+	}
+
+	router := gin.New()
+	router.Use(rowndgin.WithAuthentication(*handler))
+	router.GET("/me", func(c *gin.Context) {
+		token := rowndgin.TokenFromContext(c)
+		assert.NotNil(t, token)
+		assert.Equal(t, token, rownd.TokenFromCtx(c.Request.Context()))
+		c.String(http.StatusOK, token.UserID)
+	})
+
+	t.Run("valid token", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/me", nil)
+		req.Header.Set("Authentication", "Bearer valid")
+		rec := httptest.NewRecorder()
+
+		router.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "user_123", rec.Body.String())
+	})
+
+	t.Run("invalid token", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/me", nil)
+		req.Header.Set("Authentication", "Bearer invalid")
+		rec := httptest.NewRecorder()
+
+		router.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+
+	t.Run("missing token", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/me", nil)
+		rec := httptest.NewRecorder()
+
+		router.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+}
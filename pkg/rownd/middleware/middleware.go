@@ -49,13 +49,25 @@ func NewHandler(validator rownd.TokenValidator, opts ...HandlerOption) (*Handler
 	}
 
 	h := &Handler{
-		Validator:    validator,
-		ErrorHandler: o.errorHandler,
+		Validator:      validator,
+		TokenExtractor: o.tokenExtractor,
+		ErrorHandler:   o.errorHandler,
 	}
 
 	return h, nil
 }
 
+// Authenticate extracts the token from the request and validates it. It is shared by the
+// net/http middleware and the framework adapters so they all behave the same way.
+func (h Handler) Authenticate(r *http.Request) (*rownd.Token, error) {
+	token, err := h.TokenExtractor(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return h.Validator.Validate(r.Context(), token)
+}
+
 type HandlerOption interface {
 	apply(*handlerOptions)
 }
@@ -0,0 +1,367 @@
+package rowndmiddleware
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/rownd/client-go/pkg/rownd"
+)
+
+const (
+	headerRateLimitLimit     string = "RateLimit-Limit"
+	headerRateLimitRemaining string = "RateLimit-Remaining"
+	headerRateLimitReset     string = "RateLimit-Reset"
+	headerRetryAfter         string = "Retry-After"
+)
+
+// Limit describes a token bucket: Requests are replenished every Per, and up to Burst requests
+// may be made at once. Burst defaults to Requests.
+type Limit struct {
+	Requests int
+	Per      time.Duration
+	Burst    int
+}
+
+func (l Limit) validate() error {
+	if l.Requests <= 0 {
+		return errors.New("limit requests must be greater than zero")
+	}
+	if l.Per <= 0 {
+		return errors.New("limit period must be greater than zero")
+	}
+	if l.Burst < 0 {
+		return errors.New("limit burst must not be negative")
+	}
+	return nil
+}
+
+func (l Limit) capacity() int {
+	if l.Burst > 0 {
+		return l.Burst
+	}
+	return l.Requests
+}
+
+// RateLimitResult is the outcome of taking a request from a bucket.
+type RateLimitResult struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	Reset      time.Duration
+	RetryAfter time.Duration
+}
+
+// RateLimitStore keeps token buckets. Implementations backed by shared storage such as Redis
+// allow limits to be enforced across several processes.
+type RateLimitStore interface {
+	Take(ctx context.Context, key string, limit Limit) (RateLimitResult, error)
+}
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+	full    time.Time
+}
+
+// MemoryRateLimitStore is an in-memory RateLimitStore.
+type MemoryRateLimitStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastEvict time.Time
+}
+
+// NewMemoryRateLimitStore creates a new in-memory rate limit store.
+func NewMemoryRateLimitStore() *MemoryRateLimitStore {
+	return &MemoryRateLimitStore{
+		buckets: map[string]*bucket{},
+	}
+}
+
+// Take implements RateLimitStore.Take().
+func (s *MemoryRateLimitStore) Take(_ context.Context, key string, limit Limit) (RateLimitResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	capacity := float64(limit.capacity())
+	perToken := limit.Per / time.Duration(limit.Requests)
+
+	b, ok := s.buckets[key]
+	if !ok {
+		s.evict(now)
+		b = &bucket{tokens: capacity, updated: now}
+		s.buckets[key] = b
+	}
+
+	// refill
+	elapsed := now.Sub(b.updated)
+	b.tokens = math.Min(capacity, b.tokens+float64(elapsed)/float64(perToken))
+	b.updated = now
+
+	result := RateLimitResult{Limit: limit.capacity()}
+	if b.tokens >= 1 {
+		b.tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = time.Duration((1 - b.tokens) * float64(perToken))
+	}
+	result.Remaining = int(b.tokens)
+	result.Reset = time.Duration((capacity - b.tokens) * float64(perToken))
+	b.full = now.Add(result.Reset)
+
+	return result, nil
+}
+
+// evict drops buckets that have refilled completely, since they are equivalent to a new bucket.
+func (s *MemoryRateLimitStore) evict(now time.Time) {
+	if now.Sub(s.lastEvict) < time.Minute {
+		return
+	}
+	s.lastEvict = now
+
+	for k, b := range s.buckets {
+		if now.After(b.full) {
+			delete(s.buckets, k)
+		}
+	}
+}
+
+// RateLimitKeyFunc returns the bucket key for requests without a validated token.
+type RateLimitKeyFunc func(r *http.Request) string
+
+// RateLimitOption ...
+type RateLimitOption interface {
+	apply(*rateLimitOptions)
+}
+
+type rateLimitOptions struct {
+	store           RateLimitStore
+	defaultLimit    Limit
+	unauthenticated *Limit
+	anonymous       *Limit
+	authLevelLimits map[rownd.AuthLevel]Limit
+	keyFunc         RateLimitKeyFunc
+	exceededHandler func(w http.ResponseWriter, r *http.Request, result RateLimitResult)
+	failOpen        bool
+}
+
+func (o rateLimitOptions) validate() error {
+	var errs []error
+
+	if o.store == nil {
+		errs = append(errs, errors.New("rate limit store is required"))
+	}
+	if err := o.defaultLimit.validate(); err != nil {
+		errs = append(errs, fmt.Errorf("default limit: %w", err))
+	}
+	if o.unauthenticated != nil {
+		if err := o.unauthenticated.validate(); err != nil {
+			errs = append(errs, fmt.Errorf("unauthenticated limit: %w", err))
+		}
+	}
+	if o.anonymous != nil {
+		if err := o.anonymous.validate(); err != nil {
+			errs = append(errs, fmt.Errorf("anonymous limit: %w", err))
+		}
+	}
+	for level, limit := range o.authLevelLimits {
+		if err := limit.validate(); err != nil {
+			errs = append(errs, fmt.Errorf("%s limit: %w", level, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+type rateLimitStoreOpt struct {
+	store RateLimitStore
+}
+
+func (o rateLimitStoreOpt) apply(opts *rateLimitOptions) {
+	opts.store = o.store
+}
+
+// WithRateLimitStore sets the bucket storage. Defaults to an in-memory store.
+func WithRateLimitStore(store RateLimitStore) RateLimitOption {
+	return rateLimitStoreOpt{store: store}
+}
+
+type defaultLimitOpt Limit
+
+func (o defaultLimitOpt) apply(opts *rateLimitOptions) {
+	opts.defaultLimit = Limit(o)
+}
+
+// WithDefaultLimit sets the limit for authenticated users without a more specific limit.
+func WithDefaultLimit(limit Limit) RateLimitOption {
+	return defaultLimitOpt(limit)
+}
+
+type unauthenticatedLimitOpt Limit
+
+func (o unauthenticatedLimitOpt) apply(opts *rateLimitOptions) {
+	l := Limit(o)
+	opts.unauthenticated = &l
+}
+
+// WithUnauthenticatedLimit sets the limit for requests without a validated token. Defaults to the
+// default limit.
+func WithUnauthenticatedLimit(limit Limit) RateLimitOption {
+	return unauthenticatedLimitOpt(limit)
+}
+
+type anonymousLimitOpt Limit
+
+func (o anonymousLimitOpt) apply(opts *rateLimitOptions) {
+	l := Limit(o)
+	opts.anonymous = &l
+}
+
+// WithAnonymousLimit sets the limit for anonymous users. It takes precedence over auth level limits.
+func WithAnonymousLimit(limit Limit) RateLimitOption {
+	return anonymousLimitOpt(limit)
+}
+
+type authLevelLimitOpt struct {
+	level rownd.AuthLevel
+	limit Limit
+}
+
+func (o authLevelLimitOpt) apply(opts *rateLimitOptions) {
+	opts.authLevelLimits[o.level] = o.limit
+}
+
+// WithAuthLevelLimit sets the limit for users with the supplied auth level.
+func WithAuthLevelLimit(level rownd.AuthLevel, limit Limit) RateLimitOption {
+	return authLevelLimitOpt{level: level, limit: limit}
+}
+
+type rateLimitKeyFuncOpt struct {
+	fn RateLimitKeyFunc
+}
+
+func (o rateLimitKeyFuncOpt) apply(opts *rateLimitOptions) {
+	opts.keyFunc = o.fn
+}
+
+// WithRateLimitKeyFunc overrides how unauthenticated requests are keyed. Defaults to the remote
+// IP address; set this when running behind a trusted proxy.
+func WithRateLimitKeyFunc(fn RateLimitKeyFunc) RateLimitOption {
+	return rateLimitKeyFuncOpt{fn: fn}
+}
+
+type exceededHandlerOpt struct {
+	fn func(w http.ResponseWriter, r *http.Request, result RateLimitResult)
+}
+
+func (o exceededHandlerOpt) apply(opts *rateLimitOptions) {
+	opts.exceededHandler = o.fn
+}
+
+// WithRateLimitExceededHandler sets the handler invoked once a request is throttled. The rate
+// limit headers are already set when it runs.
+func WithRateLimitExceededHandler(fn func(w http.ResponseWriter, r *http.Request, result RateLimitResult)) RateLimitOption {
+	return exceededHandlerOpt{fn: fn}
+}
+
+type failOpenOpt bool
+
+func (o failOpenOpt) apply(opts *rateLimitOptions) {
+	opts.failOpen = bool(o)
+}
+
+// WithFailOpen lets requests through when the store returns an error. By default such requests
+// are rejected with 503 Service Unavailable.
+func WithFailOpen(failOpen bool) RateLimitOption {
+	return failOpenOpt(failOpen)
+}
+
+// RateLimit returns a middleware that throttles requests per Rownd user. Place it after
+// WithAuthentication so the validated token is available; requests without a token are keyed by
+// IP address instead.
+func RateLimit(opts ...RateLimitOption) (func(next http.Handler) http.Handler, error) {
+	o := rateLimitOptions{
+		store:           NewMemoryRateLimitStore(),
+		defaultLimit:    Limit{Requests: 60, Per: time.Minute},
+		authLevelLimits: map[rownd.AuthLevel]Limit{},
+		keyFunc:         remoteIP,
+		exceededHandler: func(w http.ResponseWriter, r *http.Request, result RateLimitResult) {
+			http.Error(w, http.StatusText(http.StatusTooManyRequests), http.StatusTooManyRequests)
+		},
+	}
+	for _, opt := range opts {
+		opt.apply(&o)
+	}
+	if err := o.validate(); err != nil {
+		return nil, err
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key, limit := o.resolve(r)
+
+			result, err := o.store.Take(r.Context(), key, limit)
+			if err != nil {
+				if o.failOpen {
+					next.ServeHTTP(w, r)
+					return
+				}
+				http.Error(w, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+				return
+			}
+
+			w.Header().Set(headerRateLimitLimit, strconv.Itoa(result.Limit))
+			w.Header().Set(headerRateLimitRemaining, strconv.Itoa(result.Remaining))
+			w.Header().Set(headerRateLimitReset, strconv.Itoa(ceilSeconds(result.Reset)))
+
+			if !result.Allowed {
+				w.Header().Set(headerRetryAfter, strconv.Itoa(ceilSeconds(result.RetryAfter)))
+				o.exceededHandler(w, r, result)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}, nil
+}
+
+// resolve picks the bucket key and limit for the request.
+func (o rateLimitOptions) resolve(r *http.Request) (string, Limit) {
+	token := rownd.TokenFromCtx(r.Context())
+	if token == nil || token.UserID == "" {
+		limit := o.defaultLimit
+		if o.unauthenticated != nil {
+			limit = *o.unauthenticated
+		}
+		return "ip:" + o.keyFunc(r), limit
+	}
+
+	key := "user:" + token.UserID
+	if token.Claims.IsAnonymous && o.anonymous != nil {
+		return key, *o.anonymous
+	}
+	if limit, ok := o.authLevelLimits[token.Claims.AuthLevel]; ok {
+		return key, limit
+	}
+
+	return key, o.defaultLimit
+}
+
+func remoteIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
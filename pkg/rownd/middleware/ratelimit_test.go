@@ -0,0 +1,73 @@
+package rowndmiddleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/rownd/client-go/pkg/rownd"
+	rowndmiddleware "github.com/rownd/client-go/pkg/rownd/middleware"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimit(t *testing.T) {
+	limiter, err := rowndmiddleware.RateLimit(
+		rowndmiddleware.WithDefaultLimit(rowndmiddleware.Limit{Requests: 3, Per: time.Minute}),
+		rowndmiddleware.WithAuthLevelLimit(rownd.AuthLevelGuest, rowndmiddleware.Limit{Requests: 1, Per: time.Minute}),
+		rowndmiddleware.WithUnauthenticatedLimit(rowndmiddleware.Limit{Requests: 2, Per: time.Minute}),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create rate limiter: %v", err)
+	}
+	srv := limiter(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	do := func(token *rownd.Token, remoteAddr string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.RemoteAddr = remoteAddr
+		if token != nil {
+			req = req.WithContext(rownd.AddTokenToCtx(req.Context(), token))
+		}
+		rec := httptest.NewRecorder()
+		srv.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("keyed by user", func(t *testing.T) {
+		token := &rownd.Token{UserID: "user_1", Claims: rownd.Claims{AuthLevel: rownd.AuthLevelVerified}}
+
+		for i := 0; i < 3; i++ {
+			rec := do(token, "10.0.0.1:1234")
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Equal(t, "3", rec.Header().Get("RateLimit-Limit"))
+		}
+
+		// a different user behind the same IP is unaffected
+		rec := do(&rownd.Token{UserID: "user_2"}, "10.0.0.1:1234")
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		rec = do(token, "10.0.0.2:1234")
+		assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+		assert.Equal(t, "0", rec.Header().Get("RateLimit-Remaining"))
+		assert.NotEmpty(t, rec.Header().Get("Retry-After"))
+	})
+
+	t.Run("auth level limit", func(t *testing.T) {
+		token := &rownd.Token{UserID: "guest_1", Claims: rownd.Claims{AuthLevel: rownd.AuthLevelGuest}}
+
+		assert.Equal(t, http.StatusOK, do(token, "10.0.0.3:1234").Code)
+		assert.Equal(t, http.StatusTooManyRequests, do(token, "10.0.0.3:1234").Code)
+	})
+
+	t.Run("falls back to IP", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, do(nil, "10.0.0.4:1234").Code)
+		assert.Equal(t, http.StatusOK, do(nil, "10.0.0.4:5678").Code)
+		assert.Equal(t, http.StatusTooManyRequests, do(nil, "10.0.0.4:1234").Code)
+		assert.Equal(t, http.StatusOK, do(nil, "10.0.0.5:1234").Code)
+	})
+
+	t.Run("invalid limit", func(t *testing.T) {
+		_, err := rowndmiddleware.RateLimit(rowndmiddleware.WithDefaultLimit(rowndmiddleware.Limit{}))
+		assert.Error(t, err)
+	})
+}
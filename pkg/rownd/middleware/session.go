@@ -0,0 +1,473 @@
+package rowndmiddleware
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	defaultSessionCookieName string        = "rownd_session"
+	defaultCSRFCookieName    string        = "rownd_csrf"
+	defaultCSRFHeader        string        = "X-CSRF-Token"
+	defaultCSRFFormField     string        = "csrf_token"
+	defaultSessionMaxAge     time.Duration = 24 * time.Hour
+
+	minSessionSecretLength int = 32
+)
+
+// CSRFMode selects how unsafe requests authenticated with a session cookie are protected against
+// cross-site request forgery.
+type CSRFMode string
+
+const (
+	// CSRFDoubleSubmit requires unsafe requests to echo the CSRF token, either in the X-CSRF-Token
+	// header or the csrf_token form field. The token is bound to the session cookie.
+	CSRFDoubleSubmit CSRFMode = "double_submit"
+	// CSRFOriginCheck requires the Origin (or Referer) of unsafe requests to match the request
+	// host or one of the trusted origins.
+	CSRFOriginCheck CSRFMode = "origin_check"
+)
+
+func (m CSRFMode) validate() bool {
+	switch m {
+	case CSRFDoubleSubmit, CSRFOriginCheck:
+		return true
+	default:
+		return false
+	}
+}
+
+var (
+	// ErrSessionNotFound is returned when the request carries no session cookie.
+	ErrSessionNotFound = errors.New("session not found")
+	// ErrSessionInvalid is returned when the session cookie was tampered with or has expired.
+	ErrSessionInvalid = errors.New("invalid session")
+	// ErrCSRF is returned when an unsafe request fails CSRF verification.
+	ErrCSRF = errors.New("csrf verification failed")
+)
+
+// SessionManager exchanges Rownd access tokens for signed, HttpOnly session cookies for
+// server-rendered applications.
+type SessionManager struct {
+	secret         []byte
+	cookieName     string
+	csrfCookieName string
+	maxAge         time.Duration
+	path           string
+	domain         string
+	secure         bool
+	sameSite       http.SameSite
+	csrfMode       CSRFMode
+	trustedOrigins []string
+}
+
+type sessionPayload struct {
+	AccessToken string `json:"t"`
+	ExpiresAt   int64  `json:"e"`
+}
+
+// NewSessionManager creates a new session manager. The secret signs session cookies and must be at
+// least 32 bytes long.
+func NewSessionManager(secret []byte, opts ...SessionOption) (*SessionManager, error) {
+	o := sessionOptions{
+		cookieName:     defaultSessionCookieName,
+		csrfCookieName: defaultCSRFCookieName,
+		maxAge:         defaultSessionMaxAge,
+		path:           "/",
+		secure:         true,
+		sameSite:       http.SameSiteLaxMode,
+		csrfMode:       CSRFDoubleSubmit,
+	}
+	for _, opt := range opts {
+		opt.apply(&o)
+	}
+	if len(secret) < minSessionSecretLength {
+		return nil, fmt.Errorf("session secret must be at least %d bytes", minSessionSecretLength)
+	}
+	if err := o.validate(); err != nil {
+		return nil, err
+	}
+
+	m := &SessionManager{
+		secret:         secret,
+		cookieName:     o.cookieName,
+		csrfCookieName: o.csrfCookieName,
+		maxAge:         o.maxAge,
+		path:           o.path,
+		domain:         o.domain,
+		secure:         o.secure,
+		sameSite:       o.sameSite,
+		csrfMode:       o.csrfMode,
+		trustedOrigins: o.trustedOrigins,
+	}
+
+	return m, nil
+}
+
+// TokenExtractor returns a TokenExtractor that reads the access token from the session cookie and
+// enforces CSRF protection on unsafe methods. Use it with NewHandler and WithAuthentication.
+func (m *SessionManager) TokenExtractor() TokenExtractor {
+	return func(r *http.Request) (string, error) {
+		payload, signature, err := m.readSession(r)
+		if err != nil {
+			return "", err
+		}
+
+		if err := m.verifyCSRF(r, signature); err != nil {
+			return "", err
+		}
+
+		return payload.AccessToken, nil
+	}
+}
+
+// LoginHandler returns an http.Handler that validates a posted access token and sets the session
+// cookie. The token is read from the "access_token" field of a JSON or form-encoded body. The CSRF
+// token for subsequent requests is returned in the X-CSRF-Token header and as {"csrf_token": "..."}.
+func (m *SessionManager) LoginHandler(handler Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+		// protect against login CSRF regardless of the configured mode.
+		if hasOrigin(r) && !m.allowedOrigin(r) {
+			handler.ErrorHandler(w, r, ErrCSRF)
+			return
+		}
+
+		accessToken, err := readAccessToken(r)
+		if err != nil || accessToken == "" {
+			http.Error(w, "access_token is required", http.StatusBadRequest)
+			return
+		}
+
+		validated, err := handler.Validator.Validate(r.Context(), accessToken)
+		if err != nil {
+			handler.ErrorHandler(w, r, errors.New("Forbidden"))
+			return
+		}
+
+		expiresAt := time.Now().Add(m.maxAge)
+		if exp := validated.Claims.Exp; exp != nil && exp.Time.Before(expiresAt) {
+			expiresAt = exp.Time
+		}
+
+		value, signature, err := m.encode(sessionPayload{AccessToken: accessToken, ExpiresAt: expiresAt.Unix()})
+		if err != nil {
+			http.Error(w, "failed to create session", http.StatusInternalServerError)
+			return
+		}
+		csrfToken := m.csrfToken(signature)
+
+		http.SetCookie(w, m.cookie(m.cookieName, value, expiresAt, true))
+		http.SetCookie(w, m.cookie(m.csrfCookieName, csrfToken, expiresAt, false))
+
+		w.Header().Set(defaultCSRFHeader, csrfToken)
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+		json.NewEncoder(w).Encode(map[string]string{"csrf_token": csrfToken})
+	})
+}
+
+// LogoutHandler returns an http.Handler that clears the session cookies. Requests carrying a valid
+// session must pass CSRF verification so third-party sites cannot log users out.
+func (m *SessionManager) LogoutHandler(handler Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, signature, err := m.readSession(r); err == nil {
+			if err := m.verifyCSRF(r, signature); err != nil {
+				handler.ErrorHandler(w, r, err)
+				return
+			}
+		}
+
+		http.SetCookie(w, m.cookie(m.cookieName, "", time.Unix(0, 0), true))
+		http.SetCookie(w, m.cookie(m.csrfCookieName, "", time.Unix(0, 0), false))
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
+
+func (m *SessionManager) cookie(name, value string, expiresAt time.Time, httpOnly bool) *http.Cookie {
+	c := &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     m.path,
+		Domain:   m.domain,
+		Expires:  expiresAt,
+		Secure:   m.secure,
+		HttpOnly: httpOnly,
+		SameSite: m.sameSite,
+	}
+	if value == "" {
+		c.MaxAge = -1
+	}
+	return c
+}
+
+func (m *SessionManager) sign(data string) []byte {
+	mac := hmac.New(sha256.New, m.secret)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+func (m *SessionManager) encode(payload sessionPayload) (string, string, error) {
+	b, err := json.Marshal(payload)
+	if err != nil {
+		return "", "", err
+	}
+
+	data := base64.RawURLEncoding.EncodeToString(b)
+	signature := base64.RawURLEncoding.EncodeToString(m.sign(data))
+
+	return data + "." + signature, signature, nil
+}
+
+func (m *SessionManager) readSession(r *http.Request) (*sessionPayload, string, error) {
+	c, err := r.Cookie(m.cookieName)
+	if err != nil || c.Value == "" {
+		return nil, "", ErrSessionNotFound
+	}
+
+	data, signature, ok := strings.Cut(c.Value, ".")
+	if !ok {
+		return nil, "", ErrSessionInvalid
+	}
+
+	decodedSignature, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil || !hmac.Equal(decodedSignature, m.sign(data)) {
+		return nil, "", ErrSessionInvalid
+	}
+
+	b, err := base64.RawURLEncoding.DecodeString(data)
+	if err != nil {
+		return nil, "", ErrSessionInvalid
+	}
+
+	var payload sessionPayload
+	if err := json.Unmarshal(b, &payload); err != nil {
+		return nil, "", ErrSessionInvalid
+	}
+	if time.Now().Unix() >= payload.ExpiresAt {
+		return nil, "", ErrSessionInvalid
+	}
+
+	return &payload, signature, nil
+}
+
+// csrfToken derives the CSRF token from the session signature so a token planted by another site
+// or subdomain cannot be paired with the victim's session.
+func (m *SessionManager) csrfToken(sessionSignature string) string {
+	return base64.RawURLEncoding.EncodeToString(m.sign("csrf:" + sessionSignature))
+}
+
+func (m *SessionManager) verifyCSRF(r *http.Request, sessionSignature string) error {
+	switch r.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+		return nil
+	}
+
+	switch m.csrfMode {
+	case CSRFOriginCheck:
+		if !m.allowedOrigin(r) {
+			return ErrCSRF
+		}
+	default:
+		submitted := r.Header.Get(defaultCSRFHeader)
+		if submitted == "" {
+			submitted = r.PostFormValue(defaultCSRFFormField)
+		}
+		if subtle.ConstantTimeCompare([]byte(submitted), []byte(m.csrfToken(sessionSignature))) != 1 {
+			return ErrCSRF
+		}
+	}
+
+	return nil
+}
+
+func hasOrigin(r *http.Request) bool {
+	return r.Header.Get("Origin") != "" || r.Header.Get("Referer") != ""
+}
+
+// allowedOrigin reports whether the Origin, or the Referer when Origin is absent, matches the
+// request host or one of the trusted origins.
+func (m *SessionManager) allowedOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		referer, err := url.Parse(r.Header.Get("Referer"))
+		if err != nil || referer.Host == "" {
+			return false
+		}
+		origin = referer.Scheme + "://" + referer.Host
+	}
+
+	u, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+	if u.Host == r.Host {
+		return true
+	}
+	for _, trusted := range m.trustedOrigins {
+		if strings.EqualFold(strings.TrimSuffix(trusted, "/"), origin) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func readAccessToken(r *http.Request) (string, error) {
+	contentType := r.Header.Get("Content-Type")
+	if strings.HasPrefix(contentType, "application/json") {
+		var body struct {
+			AccessToken string `json:"access_token"`
+		}
+		if err := json.NewDecoder(io.LimitReader(r.Body, 1<<16)).Decode(&body); err != nil {
+			return "", err
+		}
+		return body.AccessToken, nil
+	}
+
+	return r.PostFormValue("access_token"), nil
+}
+
+// SessionOption ...
+type SessionOption interface {
+	apply(*sessionOptions)
+}
+
+type sessionOptions struct {
+	cookieName     string
+	csrfCookieName string
+	maxAge         time.Duration
+	path           string
+	domain         string
+	secure         bool
+	sameSite       http.SameSite
+	csrfMode       CSRFMode
+	trustedOrigins []string
+}
+
+func (o sessionOptions) validate() error {
+	var errs []error
+
+	if o.cookieName == "" {
+		errs = append(errs, errors.New("session cookie name is required"))
+	}
+	if o.csrfCookieName == "" {
+		errs = append(errs, errors.New("csrf cookie name is required"))
+	}
+	if o.maxAge <= 0 {
+		errs = append(errs, errors.New("session max age must be greater than zero"))
+	}
+	if !o.csrfMode.validate() {
+		errs = append(errs, errors.New("invalid csrf mode"))
+	}
+
+	return errors.Join(errs...)
+}
+
+type sessionCookieNameOpt string
+
+func (o sessionCookieNameOpt) apply(opts *sessionOptions) {
+	opts.cookieName = string(o)
+}
+
+// WithSessionCookieName overrides the session cookie name. Defaults to "rownd_session".
+func WithSessionCookieName(name string) SessionOption {
+	return sessionCookieNameOpt(name)
+}
+
+type csrfCookieNameOpt string
+
+func (o csrfCookieNameOpt) apply(opts *sessionOptions) {
+	opts.csrfCookieName = string(o)
+}
+
+// WithCSRFCookieName overrides the CSRF cookie name. Defaults to "rownd_csrf".
+func WithCSRFCookieName(name string) SessionOption {
+	return csrfCookieNameOpt(name)
+}
+
+type sessionMaxAgeOpt time.Duration
+
+func (o sessionMaxAgeOpt) apply(opts *sessionOptions) {
+	opts.maxAge = time.Duration(o)
+}
+
+// WithSessionMaxAge caps the session lifetime. Sessions never outlive the access token's exp claim.
+func WithSessionMaxAge(d time.Duration) SessionOption {
+	return sessionMaxAgeOpt(d)
+}
+
+type sessionCookieScopeOpt struct {
+	path   string
+	domain string
+}
+
+func (o sessionCookieScopeOpt) apply(opts *sessionOptions) {
+	opts.path = o.path
+	opts.domain = o.domain
+}
+
+// WithSessionCookieScope sets the path and domain of the session cookies.
+func WithSessionCookieScope(path, domain string) SessionOption {
+	return sessionCookieScopeOpt{path: path, domain: domain}
+}
+
+type insecureCookiesOpt struct{}
+
+func (o insecureCookiesOpt) apply(opts *sessionOptions) {
+	opts.secure = false
+}
+
+// WithInsecureCookies drops the Secure attribute, which is only appropriate for local development
+// over plain HTTP.
+func WithInsecureCookies() SessionOption {
+	return insecureCookiesOpt{}
+}
+
+type sameSiteOpt http.SameSite
+
+func (o sameSiteOpt) apply(opts *sessionOptions) {
+	opts.sameSite = http.SameSite(o)
+}
+
+// WithSameSite sets the SameSite attribute of the session cookies. Defaults to Lax.
+func WithSameSite(sameSite http.SameSite) SessionOption {
+	return sameSiteOpt(sameSite)
+}
+
+type csrfModeOpt CSRFMode
+
+func (o csrfModeOpt) apply(opts *sessionOptions) {
+	opts.csrfMode = CSRFMode(o)
+}
+
+// WithCSRFMode selects the CSRF protection applied to unsafe methods. Defaults to CSRFDoubleSubmit.
+func WithCSRFMode(mode CSRFMode) SessionOption {
+	return csrfModeOpt(mode)
+}
+
+type trustedOriginsOpt []string
+
+func (o trustedOriginsOpt) apply(opts *sessionOptions) {
+	opts.trustedOrigins = append(opts.trustedOrigins, o...)
+}
+
+// WithTrustedOrigins adds origins, such as "https://app.example.com", that may issue unsafe
+// requests in addition to the request host.
+func WithTrustedOrigins(origins ...string) SessionOption {
+	return trustedOriginsOpt(origins)
+}
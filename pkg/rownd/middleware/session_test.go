@@ -0,0 +1,156 @@
+package rowndmiddleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/rownd/client-go/pkg/rownd"
+	rowndmiddleware "github.com/rownd/client-go/pkg/rownd/middleware"
+	"github.com/stretchr/testify/assert"
+)
+
+var sessionSecret = []byte("0123456789abcdef0123456789abcdef")
+
+func TestSessionManager(t *testing.T) {
+	sessions, err := rowndmiddleware.NewSessionManager(sessionSecret)
+	if err != nil {
+		t.Fatalf("Failed to create session manager: %v", err)
+	}
+	handler, err := rowndmiddleware.NewHandler(fakeValidator{},
+		rowndmiddleware.WithTokenExtractor(sessions.TokenExtractor()),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create handler: %v", err)
+	}
+
+	protected := rowndmiddleware.WithAuthentication(*handler)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(rownd.TokenFromCtx(r.Context()).UserID))
+	}))
+
+	login := func(accessToken string) *httptest.ResponseRecorder {
+		form := url.Values{"access_token": {accessToken}}
+		req := httptest.NewRequest(http.MethodPost, "/login", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		rec := httptest.NewRecorder()
+		sessions.LoginHandler(*handler).ServeHTTP(rec, req)
+		return rec
+	}
+
+	withCookies := func(req *http.Request, cookies []*http.Cookie) *http.Request {
+		for _, c := range cookies {
+			req.AddCookie(c)
+		}
+		return req
+	}
+
+	t.Run("rejects invalid access token", func(t *testing.T) {
+		rec := login("invalid")
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+		assert.Empty(t, rec.Result().Cookies())
+	})
+
+	rec := login("valid")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	cookies := rec.Result().Cookies()
+	csrfToken := rec.Header().Get("X-CSRF-Token")
+	assert.NotEmpty(t, csrfToken)
+
+	var sessionCookie *http.Cookie
+	for _, c := range cookies {
+		if c.Name == "rownd_session" {
+			sessionCookie = c
+		}
+	}
+	if assert.NotNil(t, sessionCookie) {
+		assert.True(t, sessionCookie.HttpOnly)
+		assert.True(t, sessionCookie.Secure)
+	}
+
+	t.Run("safe request", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		protected.ServeHTTP(rec, withCookies(httptest.NewRequest(http.MethodGet, "/", nil), cookies))
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "user_123", rec.Body.String())
+	})
+
+	t.Run("unsafe request without csrf token", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		protected.ServeHTTP(rec, withCookies(httptest.NewRequest(http.MethodPost, "/", nil), cookies))
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+
+	t.Run("unsafe request with csrf token", func(t *testing.T) {
+		req := withCookies(httptest.NewRequest(http.MethodPost, "/", nil), cookies)
+		req.Header.Set("X-CSRF-Token", csrfToken)
+		rec := httptest.NewRecorder()
+		protected.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+
+	t.Run("tampered cookie", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.AddCookie(&http.Cookie{Name: "rownd_session", Value: "e30." + strings.Repeat("A", 43)})
+		rec := httptest.NewRecorder()
+		protected.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+
+	t.Run("logout", func(t *testing.T) {
+		req := withCookies(httptest.NewRequest(http.MethodPost, "/logout", nil), cookies)
+		rec := httptest.NewRecorder()
+		sessions.LogoutHandler(*handler).ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+
+		req.Header.Set("X-CSRF-Token", csrfToken)
+		rec = httptest.NewRecorder()
+		sessions.LogoutHandler(*handler).ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+		for _, c := range rec.Result().Cookies() {
+			assert.Empty(t, c.Value)
+			assert.Less(t, c.MaxAge, 0)
+		}
+	})
+
+	t.Run("origin check", func(t *testing.T) {
+		sessions, err := rowndmiddleware.NewSessionManager(sessionSecret,
+			rowndmiddleware.WithCSRFMode(rowndmiddleware.CSRFOriginCheck),
+			rowndmiddleware.WithTrustedOrigins("https://app.example.com"),
+		)
+		if err != nil {
+			t.Fatalf("Failed to create session manager: %v", err)
+		}
+		handler, err := rowndmiddleware.NewHandler(fakeValidator{},
+			rowndmiddleware.WithTokenExtractor(sessions.TokenExtractor()),
+		)
+		if err != nil {
+			t.Fatalf("Failed to create handler: %v", err)
+		}
+		protected := rowndmiddleware.WithAuthentication(*handler)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+		req := httptest.NewRequest(http.MethodPost, "/login", strings.NewReader(`{"access_token":"valid"}`))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		sessions.LoginHandler(*handler).ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		cookies := rec.Result().Cookies()
+
+		req = withCookies(httptest.NewRequest(http.MethodPost, "/", nil), cookies)
+		req.Header.Set("Origin", "https://evil.example.com")
+		rec = httptest.NewRecorder()
+		protected.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+
+		req.Header.Set("Origin", "https://app.example.com")
+		rec = httptest.NewRecorder()
+		protected.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+
+	t.Run("short secret", func(t *testing.T) {
+		_, err := rowndmiddleware.NewSessionManager([]byte("short"))
+		assert.Error(t, err)
+	})
+}
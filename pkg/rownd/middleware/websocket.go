@@ -0,0 +1,318 @@
+package rowndmiddleware
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rownd/client-go/pkg/rownd"
+)
+
+const (
+	headerWebSocketProtocol string = "Sec-WebSocket-Protocol"
+
+	// WebSocketSubprotocol is the subprotocol a server should select when accepting an upgrade
+	// authenticated with WebSocketProtocolTokenExtractor. The token-carrying entry must never be
+	// echoed back to the browser.
+	WebSocketSubprotocol string = "rownd"
+
+	// WebSocketTokenProtocolPrefix prefixes the subprotocol entry carrying the access token, e.g.
+	// new WebSocket(url, ["rownd", "rownd.token." + accessToken]).
+	WebSocketTokenProtocolPrefix string = "rownd.token."
+
+	defaultTicketQueryParam string        = "ticket"
+	defaultTicketTTL        time.Duration = 30 * time.Second
+)
+
+var (
+	// ErrTicketNotFound is returned when a ticket is unknown, expired or already redeemed.
+	ErrTicketNotFound = errors.New("ticket not found")
+)
+
+// WebSocketProtocolTokenExtractor returns a TokenExtractor that reads the access token from the
+// Sec-WebSocket-Protocol header, since browsers cannot set an Authorization header on upgrades.
+func WebSocketProtocolTokenExtractor() TokenExtractor {
+	return func(r *http.Request) (string, error) {
+		for _, header := range r.Header.Values(headerWebSocketProtocol) {
+			for _, protocol := range strings.Split(header, ",") {
+				token, ok := strings.CutPrefix(strings.TrimSpace(protocol), WebSocketTokenProtocolPrefix)
+				if ok && token != "" {
+					return token, nil
+				}
+			}
+		}
+
+		return "", nil
+	}
+}
+
+// TicketStore issues short-lived, single-use tickets that stand in for an access token on
+// requests that cannot carry headers, such as WebSocket upgrades or EventSource connections.
+type TicketStore interface {
+	// Issue stores the access token and returns an opaque ticket for it.
+	Issue(ctx context.Context, accessToken string) (string, error)
+	// Redeem returns the access token for the ticket and invalidates the ticket.
+	Redeem(ctx context.Context, ticket string) (string, error)
+}
+
+type memoryTicket struct {
+	accessToken string
+	expiresAt   time.Time
+}
+
+// MemoryTicketStore is an in-memory TicketStore. It is only suitable when every request for a
+// ticket and its redemption are served by the same process.
+type MemoryTicketStore struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	tickets map[string]memoryTicket
+}
+
+// NewMemoryTicketStore creates a new in-memory ticket store. A ttl of zero uses a 30 second default.
+func NewMemoryTicketStore(ttl time.Duration) *MemoryTicketStore {
+	if ttl <= 0 {
+		ttl = defaultTicketTTL
+	}
+
+	return &MemoryTicketStore{
+		ttl:     ttl,
+		tickets: map[string]memoryTicket{},
+	}
+}
+
+// Issue implements TicketStore.Issue().
+func (s *MemoryTicketStore) Issue(_ context.Context, accessToken string) (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	ticket := base64.RawURLEncoding.EncodeToString(b)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	for k, v := range s.tickets {
+		if now.After(v.expiresAt) {
+			delete(s.tickets, k)
+		}
+	}
+	s.tickets[ticket] = memoryTicket{accessToken: accessToken, expiresAt: now.Add(s.ttl)}
+
+	return ticket, nil
+}
+
+// Redeem implements TicketStore.Redeem().
+func (s *MemoryTicketStore) Redeem(_ context.Context, ticket string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t, ok := s.tickets[ticket]
+	if !ok {
+		return "", ErrTicketNotFound
+	}
+	delete(s.tickets, ticket)
+
+	if time.Now().After(t.expiresAt) {
+		return "", ErrTicketNotFound
+	}
+
+	return t.accessToken, nil
+}
+
+// TicketHandler returns an http.Handler that issues a ticket for the bearer-authenticated caller.
+// The response body is {"ticket": "..."}.
+func TicketHandler(handler Handler, store TicketStore) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		validated, err := handler.Authenticate(r)
+		if err != nil {
+			handler.ErrorHandler(w, r, errors.New("Forbidden"))
+			return
+		}
+
+		ticket, err := store.Issue(r.Context(), validated.AccessToken)
+		if err != nil {
+			http.Error(w, "failed to issue ticket", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+		json.NewEncoder(w).Encode(map[string]string{"ticket": ticket})
+	})
+}
+
+// TicketTokenExtractor returns a TokenExtractor that redeems the ticket found in the "ticket"
+// query parameter. The redeemed access token is still validated by the Handler's validator.
+func TicketTokenExtractor(store TicketStore) TokenExtractor {
+	return func(r *http.Request) (string, error) {
+		ticket := r.URL.Query().Get(defaultTicketQueryParam)
+		if ticket == "" {
+			return "", nil
+		}
+
+		return store.Redeem(r.Context(), ticket)
+	}
+}
+
+// SessionWatcher tracks the expiration of the token that authenticated a long-lived connection
+// such as a WebSocket or Server-Sent Events stream.
+type SessionWatcher struct {
+	mu         sync.Mutex
+	token      *rownd.Token
+	warnBefore time.Duration
+	expiring   chan struct{}
+	expired    chan struct{}
+	warnTimer  *time.Timer
+	expTimer   *time.Timer
+	generation int
+	stopped    bool
+}
+
+// NewSessionWatcher starts watching the supplied token. Expiring is signalled warnBefore ahead of
+// the token's exp claim so the handler can ask the client to re-authenticate, and Expired is
+// signalled once exp has passed. Tokens without an exp claim never expire.
+func NewSessionWatcher(token *rownd.Token, warnBefore time.Duration) *SessionWatcher {
+	w := &SessionWatcher{
+		warnBefore: warnBefore,
+		expiring:   make(chan struct{}, 1),
+		expired:    make(chan struct{}),
+	}
+	w.schedule(token)
+
+	return w
+}
+
+// WatchSession starts a SessionWatcher for the token embedded in ctx.
+func WatchSession(ctx context.Context, warnBefore time.Duration) (*SessionWatcher, error) {
+	token := rownd.TokenFromCtx(ctx)
+	if token == nil {
+		return nil, rownd.NewError(rownd.ErrAuthentication, "token not found in context", nil)
+	}
+
+	return NewSessionWatcher(token, warnBefore), nil
+}
+
+// schedule must be called with w.mu held or before w is shared.
+func (w *SessionWatcher) schedule(token *rownd.Token) {
+	w.token = token
+	w.generation++
+
+	if token.Claims.Exp == nil {
+		return
+	}
+
+	generation := w.generation
+	untilExp := time.Until(token.Claims.Exp.Time)
+	w.expTimer = time.AfterFunc(untilExp, func() { w.fire(generation, w.expire) })
+	w.warnTimer = time.AfterFunc(untilExp-w.warnBefore, func() { w.fire(generation, w.warn) })
+}
+
+// fire runs signal unless the session was renewed since the timer was set.
+func (w *SessionWatcher) fire(generation int, signal func()) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if generation != w.generation {
+		return
+	}
+
+	signal()
+}
+
+// warn must be called with w.mu held.
+func (w *SessionWatcher) warn() {
+	select {
+	case w.expiring <- struct{}{}:
+	default:
+	}
+}
+
+// expire must be called with w.mu held.
+func (w *SessionWatcher) expire() {
+	select {
+	case <-w.expired:
+	default:
+		close(w.expired)
+	}
+}
+
+// Expiring returns a channel that receives a value each time the token is about to expire. The
+// same channel is used for the watcher's lifetime, so it keeps working across renewals.
+func (w *SessionWatcher) Expiring() <-chan struct{} {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.expiring
+}
+
+// Expired returns a channel that is closed when the token has expired. Handlers should close the
+// connection once it fires.
+func (w *SessionWatcher) Expired() <-chan struct{} {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.expired
+}
+
+// Token returns the token currently authenticating the session.
+func (w *SessionWatcher) Token() *rownd.Token {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.token
+}
+
+// Renew extends the session with a freshly validated token for the same user. It fails once the
+// session has already expired or been stopped.
+func (w *SessionWatcher) Renew(token *rownd.Token) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.stopped {
+		return rownd.NewError(rownd.ErrAuthentication, "session watcher stopped", nil)
+	}
+	select {
+	case <-w.expired:
+		return rownd.NewError(rownd.ErrAuthentication, "session has expired", nil)
+	default:
+	}
+	if token == nil || token.UserID != w.token.UserID {
+		return rownd.NewError(rownd.ErrAuthentication, "token does not belong to the session user", nil)
+	}
+
+	w.stopTimers()
+	// drop a warning about the replaced token that nobody received
+	select {
+	case <-w.expiring:
+	default:
+	}
+	w.schedule(token)
+
+	return nil
+}
+
+// Stop releases the watcher's timers. The channels are left open.
+func (w *SessionWatcher) Stop() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.stopped = true
+	w.generation++
+	w.stopTimers()
+}
+
+func (w *SessionWatcher) stopTimers() {
+	if w.expTimer != nil {
+		w.expTimer.Stop()
+	}
+	if w.warnTimer != nil {
+		w.warnTimer.Stop()
+	}
+}
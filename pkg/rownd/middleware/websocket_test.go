@@ -0,0 +1,150 @@
+package rowndmiddleware_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/rownd/client-go/pkg/rownd"
+	rowndmiddleware "github.com/rownd/client-go/pkg/rownd/middleware"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeValidator struct{}
+
+func (fakeValidator) Validate(_ context.Context, token string) (*rownd.Token, error) {
+	if token != "valid" {
+		return nil, rownd.NewError(rownd.ErrAuthentication, "invalid token", nil)
+	}
+	return &rownd.Token{UserID: "user_123", AccessToken: token}, nil
+}
+
+func TestWebSocketAuthentication(t *testing.T) {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(rownd.TokenFromCtx(r.Context()).UserID))
+	})
+
+	t.Run("subprotocol token", func(t *testing.T) {
+		handler, err := rowndmiddleware.NewHandler(fakeValidator{},
+			rowndmiddleware.WithTokenExtractor(rowndmiddleware.WebSocketProtocolTokenExtractor()),
+		)
+		if err != nil {
+			t.Fatalf("Failed to create handler: %v", err)
+		}
+		srv := rowndmiddleware.WithAuthentication(*handler)(ok)
+
+		req := httptest.NewRequest(http.MethodGet, "/ws", nil)
+		req.Header.Set("Sec-WebSocket-Protocol", rowndmiddleware.WebSocketSubprotocol+", "+rowndmiddleware.WebSocketTokenProtocolPrefix+"valid")
+		rec := httptest.NewRecorder()
+		srv.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "user_123", rec.Body.String())
+	})
+
+	t.Run("one-time ticket", func(t *testing.T) {
+		store := rowndmiddleware.NewMemoryTicketStore(time.Minute)
+
+		bearer, err := rowndmiddleware.NewHandler(fakeValidator{})
+		if err != nil {
+			t.Fatalf("Failed to create handler: %v", err)
+		}
+		req := httptest.NewRequest(http.MethodPost, "/ws/ticket", nil)
+		req.Header.Set("Authentication", "Bearer valid")
+		rec := httptest.NewRecorder()
+		rowndmiddleware.TicketHandler(*bearer, store).ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var body map[string]string
+		assert.NoError(t, json.NewDecoder(rec.Body).Decode(&body))
+		assert.NotEmpty(t, body["ticket"])
+
+		handler, err := rowndmiddleware.NewHandler(fakeValidator{},
+			rowndmiddleware.WithTokenExtractor(rowndmiddleware.TicketTokenExtractor(store)),
+		)
+		if err != nil {
+			t.Fatalf("Failed to create handler: %v", err)
+		}
+		srv := rowndmiddleware.WithAuthentication(*handler)(ok)
+
+		req = httptest.NewRequest(http.MethodGet, "/ws?ticket="+body["ticket"], nil)
+		rec = httptest.NewRecorder()
+		srv.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		// tickets are single use
+		req = httptest.NewRequest(http.MethodGet, "/ws?ticket="+body["ticket"], nil)
+		rec = httptest.NewRecorder()
+		srv.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+}
+
+func TestSessionWatcher(t *testing.T) {
+	newToken := func(ttl time.Duration) *rownd.Token {
+		return &rownd.Token{
+			UserID: "user_123",
+			Claims: rownd.Claims{Exp: jwt.NewNumericDate(time.Now().Add(ttl))},
+		}
+	}
+
+	t.Run("expires", func(t *testing.T) {
+		token := newToken(100 * time.Millisecond)
+		watcher := rowndmiddleware.NewSessionWatcher(token, 50*time.Millisecond)
+		defer watcher.Stop()
+
+		select {
+		case <-watcher.Expiring():
+		case <-time.After(time.Second):
+			t.Fatal("expected expiring signal")
+		}
+		select {
+		case <-watcher.Expired():
+		case <-time.After(time.Second):
+			t.Fatal("expected expired signal")
+		}
+
+		assert.Error(t, watcher.Renew(token))
+	})
+
+	t.Run("renew", func(t *testing.T) {
+		watcher := rowndmiddleware.NewSessionWatcher(newToken(time.Second), 0)
+		defer watcher.Stop()
+
+		renewed := newToken(time.Hour)
+		assert.NoError(t, watcher.Renew(renewed))
+		assert.Error(t, watcher.Renew(&rownd.Token{UserID: "user_456"}))
+
+		select {
+		case <-watcher.Expired():
+			t.Fatal("renewed session should not expire")
+		case <-time.After(1500 * time.Millisecond):
+		}
+		assert.Equal(t, renewed, watcher.Token())
+	})
+
+	t.Run("warns again after renew", func(t *testing.T) {
+		// warning ahead of the whole lifetime signals right away
+		watcher := rowndmiddleware.NewSessionWatcher(newToken(time.Minute), time.Hour)
+		defer watcher.Stop()
+		expiring := watcher.Expiring()
+
+		select {
+		case <-expiring:
+		case <-time.After(time.Second):
+			t.Fatal("expected expiring signal")
+		}
+		assert.NoError(t, watcher.Renew(newToken(time.Minute)))
+
+		select {
+		case <-expiring:
+		case <-time.After(time.Second):
+			t.Fatal("expected a second expiring signal on the same channel")
+		}
+		assert.Equal(t, expiring, watcher.Expiring())
+	})
+}
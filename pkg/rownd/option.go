@@ -21,6 +21,14 @@ type clientOptions struct {
 	httpClient        *http.Client
 	jwksCacheDuration time.Duration
 	wkcCacheDuration  time.Duration
+	rateLimit         float64
+	rateLimitBurst    int
+
+	membershipCacheDuration time.Duration
+	roles                   []Role
+	maxAttempts             int
+	retryDelay              time.Duration
+	observer                func(RequestInfo)
 }
 
 func (o clientOptions) validate() error {
@@ -48,6 +56,19 @@ func (o clientOptions) validate() error {
 		errs = append(errs, errors.New("JSON Web Keys cache duration must be greater than zero"))
 	}
 
+	if o.rateLimit < 0 {
+		errs = append(errs, errors.New("rate limit must not be negative"))
+	}
+	if o.membershipCacheDuration < 0 {
+		errs = append(errs, errors.New("membership cache duration must not be negative"))
+	}
+	if o.maxAttempts < 0 {
+		errs = append(errs, errors.New("max attempts must not be negative"))
+	}
+	if o.retryDelay < 0 {
+		errs = append(errs, errors.New("retry delay must not be negative"))
+	}
+
 	if len(errs) == 0 {
 		return nil
 	}
@@ -110,6 +131,81 @@ func WithJWKsCacheDuration(d time.Duration) ClientOption {
 	return jwksCacheDurationOpt(d)
 }
 
+type rateLimitOpt struct {
+	requestsPerSecond float64
+	burst             int
+}
+
+func (o rateLimitOpt) apply(opts *clientOptions) {
+	opts.rateLimit = o.requestsPerSecond
+	opts.rateLimitBurst = o.burst
+}
+
+// WithRateLimit limits the rate of API requests made by the client, including those made
+// concurrently by bulk operations. A rate of zero disables limiting.
+func WithRateLimit(requestsPerSecond float64, burst int) ClientOption {
+	return rateLimitOpt{requestsPerSecond: requestsPerSecond, burst: burst}
+}
+
+type membershipCacheDurationOpt time.Duration
+
+func (o membershipCacheDurationOpt) apply(opts *clientOptions) {
+	opts.membershipCacheDuration = time.Duration(o)
+}
+
+// WithMembershipCache caches group member lists used by membership lookups such as
+// GroupMembers.GetByUserID, and the groups read while walking a hierarchy, for the given
+// duration. Without it Groups.EffectiveRoles, and so rowndmiddleware.RequireGroupRoles, makes two
+// requests for every level of the group's hierarchy on each call. The cache is invalidated by
+// changes made through the client, but not by changes made elsewhere. Zero disables the cache.
+func WithMembershipCache(d time.Duration) ClientOption {
+	return membershipCacheDurationOpt(d)
+}
+
+type rolesOpt []Role
+
+func (o rolesOpt) apply(opts *clientOptions) {
+	opts.roles = append(opts.roles, o...)
+}
+
+// WithRoles registers the custom group roles used by the app. Once any role is registered, member
+// and invite requests with roles other than these and the built-in owner and member roles are
+// rejected before they are sent.
+func WithRoles(roles ...Role) ClientOption {
+	return rolesOpt(roles)
+}
+
+type retriesOpt struct {
+	maxAttempts int
+	delay       time.Duration
+}
+
+func (o retriesOpt) apply(opts *clientOptions) {
+	opts.maxAttempts = o.maxAttempts
+	opts.retryDelay = o.delay
+}
+
+// WithRetries sends requests up to maxAttempts times when they fail with a network error, 429 or
+// 5xx response. Only reads and idempotent writes are retried, never creates, deletes or upserts
+// with a generated user ID. The delay
+// is multiplied by the attempt number. By default requests are not retried.
+func WithRetries(maxAttempts int, delay time.Duration) ClientOption {
+	return retriesOpt{maxAttempts: maxAttempts, delay: delay}
+}
+
+type requestObserverOpt func(RequestInfo)
+
+func (o requestObserverOpt) apply(opts *clientOptions) {
+	opts.observer = o
+}
+
+// WithRequestObserver calls fn after every request attempt with the operation name, outcome and
+// duration. fn is called from the goroutine making the request and must be safe for concurrent
+// use.
+func WithRequestObserver(fn func(RequestInfo)) ClientOption {
+	return requestObserverOpt(fn)
+}
+
 // RequestOption ...
 type RequestOption interface {
 	apply(req *http.Request)
@@ -0,0 +1,69 @@
+package rownd_test
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rownd/client-go/pkg/rownd"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestRetriesAndObserver(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		attempts = map[string]int{}
+		infos    []rownd.RequestInfo
+	)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/applications/", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		attempts[r.Method]++
+		if attempts[r.Method] < 3 {
+			writeJSON(w, http.StatusServiceUnavailable, map[string]any{"message": "try again"})
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"id": "group_1", "name": "Team"})
+	})
+	client := newTestClient(t, mux,
+		rownd.WithRetries(3, time.Millisecond),
+		rownd.WithRequestObserver(func(info rownd.RequestInfo) {
+			mu.Lock()
+			defer mu.Unlock()
+			infos = append(infos, info)
+		}),
+	)
+	ctx := context.Background()
+
+	t.Run("reads are retried", func(t *testing.T) {
+		infos = nil
+
+		group, err := client.Groups.Get(ctx, rownd.GetGroupRequest{GroupID: "group_1"})
+		assert.NoError(t, err)
+		assert.Equal(t, "Team", group.Name)
+		assert.Equal(t, 3, attempts[http.MethodGet])
+
+		assert.Len(t, infos, 3)
+		assert.Equal(t, "groups.get", infos[0].Operation)
+		assert.Equal(t, http.StatusServiceUnavailable, infos[0].StatusCode)
+		assert.Error(t, infos[0].Err)
+		assert.Equal(t, 3, infos[2].Attempt)
+		assert.Equal(t, http.StatusOK, infos[2].StatusCode)
+		assert.NoError(t, infos[2].Err)
+	})
+
+	t.Run("creates are not retried", func(t *testing.T) {
+		infos = nil
+
+		_, err := client.Groups.Create(ctx, rownd.CreateGroupRequest{Name: "Team", AdmissionPolicy: rownd.AdmissionPolicyOpen})
+		assert.Error(t, err)
+		assert.Equal(t, 1, attempts[http.MethodPost])
+		assert.Len(t, infos, 1)
+		assert.Equal(t, "groups.create", infos[0].Operation)
+	})
+}
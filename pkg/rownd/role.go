@@ -0,0 +1,117 @@
+package rownd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Role is a group role.
+type Role string
+
+// Built-in roles. Every group has at least one owner.
+const (
+	RoleOwner  Role = "owner"
+	RoleMember Role = "member"
+)
+
+// RoleSet is the set of roles held by a group member or granted by an invite. The helpers return
+// new sets and never modify the receiver.
+type RoleSet []Role
+
+// Has reports whether the set contains the role.
+func (s RoleSet) Has(role Role) bool {
+	for _, r := range s {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// Add returns the set with the roles added. Roles already present are not repeated.
+func (s RoleSet) Add(roles ...Role) RoleSet {
+	result := append(RoleSet{}, s...)
+	for _, role := range roles {
+		if !result.Has(role) {
+			result = append(result, role)
+		}
+	}
+	return result
+}
+
+// Remove returns the set without the roles.
+func (s RoleSet) Remove(roles ...Role) RoleSet {
+	result := RoleSet{}
+	for _, r := range s {
+		if !RoleSet(roles).Has(r) {
+			result = append(result, r)
+		}
+	}
+	return result
+}
+
+func (s RoleSet) validate() []error {
+	var errs []error
+	for _, role := range s {
+		if strings.TrimSpace(string(role)) == "" {
+			errs = append(errs, NewError(ErrValidation, "roles must not be empty", nil))
+		}
+	}
+	return errs
+}
+
+// MemberState is the state of a group member.
+type MemberState string
+
+const (
+	MemberStateActive  MemberState = "active"
+	MemberStatePending MemberState = "pending"
+	// MemberStateInvitePending is the state of a member added by an invite that has not been
+	// accepted yet.
+	MemberStateInvitePending MemberState = "invite_pending"
+	MemberStateSuspended     MemberState = "suspended"
+)
+
+func (s MemberState) validate() bool {
+	switch s {
+	case MemberStateActive, MemberStatePending, MemberStateInvitePending, MemberStateSuspended:
+		return true
+	default:
+		return false
+	}
+}
+
+// isActive reports whether the state is active. Members without a state are active.
+func (s MemberState) isActive() bool {
+	return s == "" || s == MemberStateActive
+}
+
+// InviteState is the state of a group invite.
+type InviteState string
+
+const (
+	InviteStatePending  InviteState = "pending"
+	InviteStateAccepted InviteState = "accepted"
+)
+
+// checkRoles rejects roles that are neither built in nor registered with WithRoles. Without a
+// registry every role is accepted.
+func (c *Client) checkRoles(roles RoleSet) error {
+	if len(c.roles) == 0 {
+		return nil
+	}
+
+	var unknown []string
+	for _, role := range roles {
+		if role != RoleOwner && role != RoleMember && !c.roles[role] {
+			unknown = append(unknown, string(role))
+		}
+	}
+	if len(unknown) == 0 {
+		return nil
+	}
+
+	sort.Strings(unknown)
+	return NewError(ErrValidation, fmt.Sprintf("unknown roles: %s", strings.Join(unknown, ", ")), nil)
+}
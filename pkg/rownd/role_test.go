@@ -0,0 +1,84 @@
+package rownd_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/rownd/client-go/pkg/rownd"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoleSet(t *testing.T) {
+	roles := rownd.RoleSet{rownd.RoleMember}
+
+	added := roles.Add(rownd.RoleOwner, rownd.RoleMember, "admin")
+	assert.Equal(t, rownd.RoleSet{"member", "owner", "admin"}, added)
+	assert.Equal(t, rownd.RoleSet{"member"}, roles, "Add must not modify the receiver")
+	assert.True(t, added.Has(rownd.RoleOwner))
+	assert.False(t, roles.Has(rownd.RoleOwner))
+
+	assert.Equal(t, rownd.RoleSet{"member"}, added.Remove(rownd.RoleOwner, "admin"))
+	assert.Equal(t, rownd.RoleSet{}, rownd.RoleSet(nil).Remove(rownd.RoleOwner))
+}
+
+func TestRoleValidation(t *testing.T) {
+	var requests int
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/applications/", func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		writeJSON(w, http.StatusOK, map[string]any{})
+	})
+	client := newTestClient(t, mux, rownd.WithRoles("admin"))
+	ctx := context.Background()
+
+	for name, fn := range map[string]func() error{
+		"unknown role": func() error {
+			_, err := client.GroupMembers.Create(ctx, rownd.CreateGroupMemberRequest{
+				GroupID: "group_1", UserID: "user_1", Roles: rownd.RoleSet{"admin", "superuser"},
+			})
+			return err
+		},
+		"empty role": func() error {
+			_, err := client.GroupMembers.Create(ctx, rownd.CreateGroupMemberRequest{
+				GroupID: "group_1", UserID: "user_1", Roles: rownd.RoleSet{""},
+			})
+			return err
+		},
+		"missing user": func() error {
+			_, err := client.GroupMembers.Create(ctx, rownd.CreateGroupMemberRequest{GroupID: "group_1"})
+			return err
+		},
+		"invalid state": func() error {
+			_, err := client.GroupMembers.Update(ctx, rownd.UpdateGroupMemberRequest{
+				GroupID: "group_1", MemberID: "member_1", Roles: rownd.RoleSet{rownd.RoleOwner}, State: "banned",
+			})
+			return err
+		},
+		"unknown invite role": func() error {
+			_, err := client.GroupInvites.Create(ctx, rownd.CreateGroupInviteRequest{
+				GroupID: "group_1", Email: "a@example.com", Roles: rownd.RoleSet{"superuser"},
+			})
+			return err
+		},
+	} {
+		assert.Equal(t, rownd.ErrValidation, rownd.KindOf(fn()), name)
+	}
+	assert.Zero(t, requests)
+
+	_, err := client.GroupMembers.Create(ctx, rownd.CreateGroupMemberRequest{
+		GroupID: "group_1", UserID: "user_1", Roles: rownd.RoleSet{rownd.RoleMember, "admin"}, State: rownd.MemberStateActive,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, requests)
+
+	// states the API reports for existing members can be sent back
+	for _, state := range []rownd.MemberState{rownd.MemberStateInvitePending, rownd.MemberStateSuspended} {
+		_, err := client.GroupMembers.Create(ctx, rownd.CreateGroupMemberRequest{
+			GroupID: "group_1", UserID: "user_1", Roles: rownd.RoleSet{rownd.RoleMember}, State: state,
+		})
+		assert.NoError(t, err, state)
+	}
+	assert.Equal(t, 3, requests)
+}
@@ -14,6 +14,7 @@ import (
 	"time"
 
 	"github.com/patrickmn/go-cache"
+	"github.com/rownd/client-go/internal/config"
 )
 
 type Sort string
@@ -34,11 +35,13 @@ const (
 
 	cacheKeyWKC  string = "wkc"
 	cacheKeyJWKS string = "jwks"
+	// cacheKeyMembersPrefix is followed by the group id.
+	cacheKeyMembersPrefix string = "members:"
+	// cacheKeyGroupPrefix is followed by the group id.
+	cacheKeyGroupPrefix string = "group:"
 
 	defaultWKCCacheDuration  time.Duration = 1 * time.Hour
 	defaultJWKsCacheDuration time.Duration = 1 * time.Hour
-
-	defaultJWKSPath = "/hub/auth/keys"
 )
 
 // ClientConfig contains the configuration for creating a new Rownd client
@@ -52,16 +55,30 @@ type ClientConfig struct {
 // Client ...
 type Client struct {
 	appID          string
+	appSchema      map[string]AppSchemaField
 	appKey         string
 	appSecret      string
 	baseURL        string
 	httpClient     *http.Client
 	httpClientOpts []RequestOption
+	limiter        *rateLimiter
+	endpoints      config.Endpoints
+
+	// retries of retryable routes, set with WithRetries
+	maxAttempts int
+	retryDelay  time.Duration
+
+	// called after every request attempt, set with WithRequestObserver
+	observer func(RequestInfo)
 
 	// cache and cache timeouts
-	cache             *cache.Cache
-	wkcCacheDuration  time.Duration
-	jwksCacheDuration time.Duration
+	cache                   *cache.Cache
+	wkcCacheDuration        time.Duration
+	jwksCacheDuration       time.Duration
+	membershipCacheDuration time.Duration
+
+	// roles registered with WithRoles
+	roles map[Role]bool
 
 	// client implementations
 	AppConfig    *appConfigClient
@@ -99,14 +116,30 @@ func NewClient(opts ...ClientOption) (*Client, error) {
 		appSecret:  o.appSecret,
 		baseURL:    o.baseURL,
 		httpClient: o.httpClient,
+		endpoints:  config.NewEndpoints(),
 		httpClientOpts: []RequestOption{
 			RequestWithHeader(headerRowndAppKey, o.appKey),
 			RequestWithHeader(headerRowndAppSecret, o.appSecret),
 		},
-		cache:             cache.New(defaultCacheTTL, defaultCacheCleanupInterval),
-		wkcCacheDuration:  defaultWKCCacheDuration,
-		jwksCacheDuration: defaultJWKsCacheDuration,
-		logger:            log.New(os.Stdout, "[rownd] ", log.LstdFlags),
+		cache:                   cache.New(defaultCacheTTL, defaultCacheCleanupInterval),
+		wkcCacheDuration:        defaultWKCCacheDuration,
+		jwksCacheDuration:       defaultJWKsCacheDuration,
+		membershipCacheDuration: o.membershipCacheDuration,
+		maxAttempts:             o.maxAttempts,
+		retryDelay:              o.retryDelay,
+		observer:                o.observer,
+		logger:                  log.New(os.Stdout, "[rownd] ", log.LstdFlags),
+	}
+
+	if len(o.roles) > 0 {
+		c.roles = make(map[Role]bool, len(o.roles))
+		for _, role := range o.roles {
+			c.roles[role] = true
+		}
+	}
+
+	if o.rateLimit > 0 {
+		c.limiter = newRateLimiter(o.rateLimit, o.rateLimitBurst)
 	}
 
 	// build client implementations
@@ -127,77 +160,148 @@ func NewClient(opts ...ClientOption) (*Client, error) {
 	return c, nil
 }
 
-// rowndURL ...
-func (c *Client) rowndURL(parts ...string) (*url.URL, error) {
+// RequestInfo describes a request sent by the client, for logging, metrics and tracing.
+type RequestInfo struct {
+	// Operation is a stable name for the API endpoint, e.g. "groups.get".
+	Operation string
+	Method    string
+	URL       string
+	// Attempt is 1 for the first try and counts up on retries.
+	Attempt int
+	// StatusCode is 0 when no response was received.
+	StatusCode int
+	Duration   time.Duration
+	Err        error
+}
+
+// endpoint is a route with its path parameters filled in.
+type endpoint struct {
+	config.Route
+	URL *url.URL
+}
+
+// endpoint resolves route against the base URL, filling its path parameters in order.
+func (c *Client) endpoint(route config.Route, params ...string) (*endpoint, error) {
+	segments, err := route.Segments(params...)
+	if err != nil {
+		return nil, err
+	}
+
 	baseURL := c.baseURL
-	if parts[0] == defaultJWKSPath {
-		// For JWKS, use the base domain without /v1
+	if route.Unversioned {
 		baseURL = strings.TrimSuffix(baseURL, "/v1")
 	}
 
-	endpoint, err := url.Parse(baseURL)
+	u, err := url.Parse(baseURL)
 	if err != nil {
 		return nil, err
 	}
 
-	// Use JoinPath instead of path.Join for URL paths
-	endpoint = endpoint.JoinPath(parts...)
-
-	return endpoint, nil
+	return &endpoint{Route: route, URL: u.JoinPath(segments...)}, nil
 }
 
-// request performs an HTTP request and unmarshals the response into v.
-func (c *Client) request(ctx context.Context, method, url string, body, v interface{}, opts ...RequestOption) error {
-	var buf bytes.Buffer
+// request sends a request to the endpoint and unmarshals the response into v. Requests to
+// retryable routes are retried when WithRetries is set.
+func (c *Client) request(ctx context.Context, e *endpoint, body, v interface{}) error {
+	var payload []byte
 	if body != nil {
+		var buf bytes.Buffer
 		if err := json.NewEncoder(&buf).Encode(body); err != nil {
 			return fmt.Errorf("failed to marshal request payload: %w", err)
 		}
+		payload = buf.Bytes()
 	}
 
+	attempts := 1
+	if e.Retryable && c.maxAttempts > 1 {
+		attempts = c.maxAttempts
+	}
+
+	var err error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		if attempt > 1 {
+			select {
+			case <-ctx.Done():
+				return err
+			case <-time.After(time.Duration(attempt-1) * c.retryDelay):
+			}
+		}
+
+		start := time.Now()
+		var status int
+		status, err = c.send(ctx, e, payload, v)
+		if c.observer != nil {
+			c.observer(RequestInfo{
+				Operation:  e.Operation,
+				Method:     e.Method,
+				URL:        e.URL.String(),
+				Attempt:    attempt,
+				StatusCode: status,
+				Duration:   time.Since(start),
+				Err:        err,
+			})
+		}
+		if err == nil || !isRetryable(err) {
+			return err
+		}
+	}
+
+	return err
+}
+
+// send performs a single HTTP request, returning the response status code if there was one.
+func (c *Client) send(ctx context.Context, e *endpoint, payload []byte, v interface{}) (int, error) {
 	// build HTTP request from arguments
-	req, err := http.NewRequestWithContext(ctx, method, url, &buf)
+	req, err := http.NewRequestWithContext(ctx, e.Method, e.URL.String(), bytes.NewReader(payload))
 	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
+		return 0, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	req.Header.Set("Content-Type", "application/json")
 
+	if c.limiter != nil {
+		if err := c.limiter.Wait(ctx); err != nil {
+			return 0, fmt.Errorf("failed to execute request: %w", err)
+		}
+	}
+
 	// Apply request options
-	for _, opt := range opts {
-		opt.apply(req)
+	if !e.Public {
+		for _, opt := range c.httpClientOpts {
+			opt.apply(req)
+		}
 	}
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return fmt.Errorf("failed to execute request: %w", err)
+		return 0, fmt.Errorf("failed to execute request: %w", err)
 	}
 	defer resp.Body.Close()
 
 	// Check for non-2xx responses and handle them
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return handleErrorResponse(resp)
+		return resp.StatusCode, handleErrorResponse(resp)
 	}
 
 	// Read response body
 	respBody, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return fmt.Errorf("failed to read response body: %w", err)
+		return resp.StatusCode, fmt.Errorf("failed to read response body: %w", err)
 	}
 
 	// For DELETE requests or empty responses, return nil
-	if method == http.MethodDelete || len(respBody) == 0 {
-		return nil
+	if e.Method == http.MethodDelete || len(respBody) == 0 {
+		return resp.StatusCode, nil
 	}
 
 	// Only try to unmarshal if we have a response target
 	if v != nil {
 		if err := json.Unmarshal(respBody, v); err != nil {
-			return fmt.Errorf("failed to unmarshal response body: %w", err)
+			return resp.StatusCode, fmt.Errorf("failed to unmarshal response body: %w", err)
 		}
 	}
 
-	return nil
+	return resp.StatusCode, nil
 }
 
 // JWK represents a JSON Web Key.
@@ -237,13 +341,13 @@ func (c *Client) fetchJWKS(ctx context.Context) (*JWKs, error) {
 		return v, nil
 	}
 
-	endpoint, err := c.rowndURL(defaultJWKSPath)
+	endpoint, err := c.endpoint(c.endpoints.JWKS)
 	if err != nil {
 		return nil, err
 	}
 
 	var response *JWKs
-	if err := c.request(ctx, http.MethodGet, endpoint.String(), nil, &response); err != nil {
+	if err := c.request(ctx, endpoint, nil, &response); err != nil {
 		return nil, NewError(ErrAPI, "failed to fetch JWKS", err)
 	}
 
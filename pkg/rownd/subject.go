@@ -0,0 +1,313 @@
+package rownd
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+const (
+	subjectDataBundleVersion int = 1
+)
+
+// SubjectDataBundle is everything the SDK can read about a user, for answering data subject access
+// requests.
+type SubjectDataBundle struct {
+	Version          int                      `json:"version"`
+	GeneratedAt      time.Time                `json:"generated_at"`
+	AppID            string                   `json:"app_id"`
+	UserID           string                   `json:"user_id"`
+	Profile          *User                    `json:"profile"`
+	GroupMemberships []SubjectGroupMembership `json:"group_memberships"`
+	PendingInvites   []GroupInvite            `json:"pending_invites"`
+}
+
+// SubjectGroupMembership is a group the subject belongs to and their membership in it.
+type SubjectGroupMembership struct {
+	Group  Group       `json:"group"`
+	Member GroupMember `json:"member"`
+}
+
+// subjectMembership adds the other members of the group, which erasure needs to keep the group
+// consistent with the ownership rules.
+type subjectMembership struct {
+	SubjectGroupMembership
+	others []GroupMember
+}
+
+// collectSubjectGroups scans every group for memberships and invites of the user.
+func (c *userClient) collectSubjectGroups(ctx context.Context, userID string) ([]subjectMembership, []GroupInvite, error) {
+	var (
+		memberships []subjectMembership
+		invites     []GroupInvite
+	)
+
+	err := c.Groups.eachGroup(ctx, func(group Group) error {
+		var (
+			membership *subjectMembership
+			others     []GroupMember
+		)
+		err := c.GroupMembers.eachMember(ctx, group.ID, func(member GroupMember) error {
+			if member.UserID == userID {
+				membership = &subjectMembership{SubjectGroupMembership: SubjectGroupMembership{Group: group, Member: member}}
+			} else {
+				others = append(others, member)
+			}
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("failed to list members of group %s: %w", group.ID, err)
+		}
+		if membership != nil {
+			membership.others = others
+			memberships = append(memberships, *membership)
+		}
+
+		err = c.GroupInvites.eachInvite(ctx, ListGroupInvitesRequest{GroupID: group.ID, EnsuredUserID: ToPointer(userID)}, func(invite GroupInvite) error {
+			if invite.AcceptedBy == "" {
+				invites = append(invites, invite)
+			}
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("failed to list invites of group %s: %w", group.ID, err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return memberships, invites, nil
+}
+
+// ExportSubjectData gathers the user's profile, verified data, group memberships and pending
+// invites into a single bundle. Marshal the bundle with encoding/json for a machine-readable copy.
+func (c *userClient) ExportSubjectData(ctx context.Context, userID string) (*SubjectDataBundle, error) {
+	if userID == "" {
+		return nil, NewError(ErrValidation, "user id is required", nil)
+	}
+
+	profile, err := c.Get(ctx, GetUserRequest{UserID: userID})
+	if err != nil {
+		return nil, err
+	}
+
+	memberships, invites, err := c.collectSubjectGroups(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	bundle := &SubjectDataBundle{
+		Version:          subjectDataBundleVersion,
+		GeneratedAt:      time.Now().UTC(),
+		AppID:            c.appID,
+		UserID:           userID,
+		Profile:          profile,
+		GroupMemberships: make([]SubjectGroupMembership, 0, len(memberships)),
+		PendingInvites:   invites,
+	}
+	for _, m := range memberships {
+		bundle.GroupMemberships = append(bundle.GroupMemberships, m.SubjectGroupMembership)
+	}
+	if bundle.PendingInvites == nil {
+		bundle.PendingInvites = []GroupInvite{}
+	}
+
+	return bundle, nil
+}
+
+// EraseOptions configures how EraseSubject handles groups that depend on the subject.
+type EraseOptions struct {
+	// TransferOwnership promotes the first other active member to owner when the subject is the
+	// only owner of a group. Without it, such groups stop the erasure before anything is changed.
+	TransferOwnership bool
+
+	// DeleteSoleMemberGroups deletes groups in which the subject is the only member, since the
+	// last member of a group cannot be removed. Without it, such groups stop the erasure before
+	// anything is changed.
+	DeleteSoleMemberGroups bool
+}
+
+// ErasureAction is a step performed while erasing a subject.
+type ErasureAction string
+
+const (
+	ErasureActionRevokeInvite     ErasureAction = "revoke_invite"
+	ErasureActionTransferOwner    ErasureAction = "transfer_ownership"
+	ErasureActionDeleteGroup      ErasureAction = "delete_group"
+	ErasureActionRemoveMembership ErasureAction = "remove_membership"
+	ErasureActionDeleteUser       ErasureAction = "delete_user"
+)
+
+// ErasureStep records a single step of an erasure.
+type ErasureStep struct {
+	Action      ErasureAction `json:"action"`
+	GroupID     string        `json:"group_id,omitempty"`
+	ResourceID  string        `json:"resource_id"`
+	PerformedAt time.Time     `json:"performed_at"`
+	Error       string        `json:"error,omitempty"`
+}
+
+// ErasureReceipt is an auditable record of an erasure.
+type ErasureReceipt struct {
+	AppID       string        `json:"app_id"`
+	UserID      string        `json:"user_id"`
+	StartedAt   time.Time     `json:"started_at"`
+	CompletedAt time.Time     `json:"completed_at"`
+	Completed   bool          `json:"completed"`
+	Steps       []ErasureStep `json:"steps"`
+}
+
+type erasureStep struct {
+	ErasureStep
+	run func(ctx context.Context) error
+}
+
+// EraseSubject removes the user's group memberships, revokes their outstanding invites and deletes
+// the user. Groups that would be left without an owner or without members are handled according
+// to opts; every group is checked before any change is made. The receipt lists the steps performed
+// and is returned even when a step fails.
+func (c *userClient) EraseSubject(ctx context.Context, userID string, opts EraseOptions) (*ErasureReceipt, error) {
+	if userID == "" {
+		return nil, NewError(ErrValidation, "user id is required", nil)
+	}
+
+	receipt := &ErasureReceipt{
+		AppID:     c.appID,
+		UserID:    userID,
+		StartedAt: time.Now().UTC(),
+		Steps:     []ErasureStep{},
+	}
+
+	memberships, invites, err := c.collectSubjectGroups(ctx, userID)
+	if err != nil {
+		return receipt, err
+	}
+
+	steps, err := c.planErasure(userID, memberships, invites, opts)
+	if err != nil {
+		return receipt, err
+	}
+
+	for _, step := range steps {
+		err := step.run(ctx)
+		step.PerformedAt = time.Now().UTC()
+		if err != nil {
+			step.Error = err.Error()
+		}
+		receipt.Steps = append(receipt.Steps, step.ErasureStep)
+		if err != nil {
+			return receipt, fmt.Errorf("erasure step %s %s failed: %w", step.Action, step.ResourceID, err)
+		}
+	}
+
+	receipt.Completed = true
+	receipt.CompletedAt = time.Now().UTC()
+
+	return receipt, nil
+}
+
+// planErasure orders the steps so that no group is ever left without an owner: invites are
+// revoked, ownership is transferred and sole-member groups are deleted before memberships are
+// removed and the user is deleted.
+func (c *userClient) planErasure(userID string, memberships []subjectMembership, invites []GroupInvite, opts EraseOptions) ([]erasureStep, error) {
+	var (
+		errs     []error
+		steps    []erasureStep
+		removals []erasureStep
+	)
+
+	for _, invite := range invites {
+		invite := invite
+		steps = append(steps, erasureStep{
+			ErasureStep: ErasureStep{Action: ErasureActionRevokeInvite, GroupID: invite.GroupID, ResourceID: invite.ID},
+			run: func(ctx context.Context) error {
+				return c.GroupInvites.Delete(ctx, DeleteGroupInviteRequest{GroupID: invite.GroupID, InviteID: invite.ID})
+			},
+		})
+	}
+
+	for _, m := range memberships {
+		m := m
+		groupID := m.Group.ID
+
+		if len(m.others) == 0 {
+			if !opts.DeleteSoleMemberGroups {
+				errs = append(errs, NewError(ErrValidation, fmt.Sprintf("user is the only member of group %s", groupID), nil))
+				continue
+			}
+			steps = append(steps, erasureStep{
+				ErasureStep: ErasureStep{Action: ErasureActionDeleteGroup, GroupID: groupID, ResourceID: groupID},
+				run: func(ctx context.Context) error {
+					return c.Groups.Delete(ctx, DeleteGroupRequest{GroupID: groupID})
+				},
+			})
+			continue
+		}
+
+		if m.Member.isActiveOwner() {
+			var (
+				otherOwner bool
+				candidate  *GroupMember
+			)
+			for i, other := range m.others {
+				if other.isActiveOwner() {
+					otherOwner = true
+					break
+				}
+				if candidate == nil && other.State.isActive() {
+					candidate = &m.others[i]
+				}
+			}
+
+			if !otherOwner {
+				switch {
+				case !opts.TransferOwnership:
+					errs = append(errs, NewError(ErrLastOwner, fmt.Sprintf("user is the only owner of group %s", groupID), nil))
+					continue
+				case candidate == nil:
+					errs = append(errs, NewError(ErrValidation, fmt.Sprintf("group %s has no active member to transfer ownership to", groupID), nil))
+					continue
+				}
+
+				newOwner := *candidate
+				steps = append(steps, erasureStep{
+					ErasureStep: ErasureStep{Action: ErasureActionTransferOwner, GroupID: groupID, ResourceID: newOwner.ID},
+					run: func(ctx context.Context) error {
+						_, err := c.GroupMembers.Update(ctx, UpdateGroupMemberRequest{
+							GroupID:  groupID,
+							MemberID: newOwner.ID,
+							UserID:   newOwner.UserID,
+							Roles:    append(RoleSet{RoleOwner}, newOwner.Roles...),
+							State:    newOwner.State,
+						})
+						return err
+					},
+				})
+			}
+		}
+
+		removals = append(removals, erasureStep{
+			ErasureStep: ErasureStep{Action: ErasureActionRemoveMembership, GroupID: groupID, ResourceID: m.Member.ID},
+			run: func(ctx context.Context) error {
+				return c.GroupMembers.Delete(ctx, DeleteGroupMemberRequest{GroupID: groupID, MemberID: m.Member.ID})
+			},
+		})
+	}
+
+	if len(errs) > 0 {
+		return nil, &MultiError{errors: errs}
+	}
+
+	steps = append(steps, removals...)
+	steps = append(steps, erasureStep{
+		ErasureStep: ErasureStep{Action: ErasureActionDeleteUser, ResourceID: userID},
+		run: func(ctx context.Context) error {
+			return c.Delete(ctx, DeleteUserRequest{UserID: userID})
+		},
+	})
+
+	return steps, nil
+}
@@ -0,0 +1,130 @@
+package rownd_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/rownd/client-go/pkg/rownd"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSubjectRequests(t *testing.T) {
+	var (
+		mu    sync.Mutex
+		calls []string
+	)
+
+	groups := []map[string]any{{"id": "group_shared"}, {"id": "group_solo"}, {"id": "group_other"}}
+	members := map[string][]map[string]any{
+		"group_shared": {
+			{"id": "member_subject", "user_id": "user_subject", "roles": []string{"owner"}, "state": "active"},
+			// a suspended owner does not keep the group owned
+			{"id": "member_suspended", "user_id": "user_suspended", "roles": []string{"owner"}, "state": "suspended"},
+			{"id": "member_peer", "user_id": "user_peer", "roles": []string{"member"}, "state": "active"},
+		},
+		"group_solo": {
+			{"id": "member_solo", "user_id": "user_subject", "roles": []string{"owner"}, "state": "active"},
+		},
+		"group_other": {
+			{"id": "member_x", "user_id": "user_x", "roles": []string{"owner"}, "state": "active"},
+		},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/applications/", func(w http.ResponseWriter, r *http.Request) {
+		segments := pathSegments(r)
+		mu.Lock()
+		defer mu.Unlock()
+
+		if r.Method != http.MethodGet {
+			calls = append(calls, r.Method+" "+r.URL.Path)
+		}
+
+		switch {
+		case segments[2] == "users" && r.Method == http.MethodGet:
+			writeJSON(w, http.StatusOK, map[string]any{"data": map[string]any{"email": "subject@example.com"}})
+		case segments[2] == "users":
+			w.WriteHeader(http.StatusNoContent)
+		case len(segments) == 3 && r.Method == http.MethodGet:
+			writeJSON(w, http.StatusOK, map[string]any{"total_results": len(groups), "results": groups})
+		case len(segments) == 5 && segments[4] == "members" && r.Method == http.MethodGet:
+			writeJSON(w, http.StatusOK, map[string]any{"results": members[segments[3]]})
+		case len(segments) == 6 && segments[4] == "members" && r.Method == http.MethodGet:
+			writeMember(w, members, r)
+		case len(segments) == 5 && segments[4] == "invites":
+			var results []map[string]any
+			if segments[3] == "group_other" && r.URL.Query().Get("ensured_user_id") == "user_subject" {
+				// a full first page of accepted invites, with the pending one on the second page
+				if r.URL.Query().Get("after") == "" {
+					for i := 0; i < 100; i++ {
+						results = append(results, map[string]any{"id": fmt.Sprintf("accepted_%d", i), "accepted_by": "user_subject"})
+					}
+				} else {
+					results = append(results, map[string]any{"id": "invite_1", "group_id": "group_other", "state": "pending"})
+				}
+			}
+			writeJSON(w, http.StatusOK, map[string]any{"results": results})
+		case r.Method == http.MethodPut:
+			var body map[string]any
+			json.NewDecoder(r.Body).Decode(&body)
+			applyMemberWrite(members, r, body)
+			writeJSON(w, http.StatusOK, body)
+		default:
+			applyMemberWrite(members, r, nil)
+			w.WriteHeader(http.StatusNoContent)
+		}
+	})
+
+	client := newTestClient(t, mux)
+	ctx := context.Background()
+
+	t.Run("export subject data", func(t *testing.T) {
+		bundle, err := client.Users.ExportSubjectData(ctx, "user_subject")
+		assert.NoError(t, err)
+		assert.Equal(t, 1, bundle.Version)
+		assert.Equal(t, "user_subject", bundle.UserID)
+		assert.Equal(t, "subject@example.com", bundle.Profile.Data["email"])
+		assert.Len(t, bundle.GroupMemberships, 2)
+		assert.Len(t, bundle.PendingInvites, 1)
+		assert.Empty(t, calls, "export must not modify anything")
+	})
+
+	t.Run("erase refuses to orphan groups", func(t *testing.T) {
+		receipt, err := client.Users.EraseSubject(ctx, "user_subject", rownd.EraseOptions{})
+		assert.Error(t, err)
+		assert.False(t, receipt.Completed)
+		assert.Empty(t, calls)
+	})
+
+	t.Run("erase", func(t *testing.T) {
+		receipt, err := client.Users.EraseSubject(ctx, "user_subject", rownd.EraseOptions{
+			TransferOwnership:      true,
+			DeleteSoleMemberGroups: true,
+		})
+		assert.NoError(t, err)
+		assert.True(t, receipt.Completed)
+
+		var actions []rownd.ErasureAction
+		for _, step := range receipt.Steps {
+			actions = append(actions, step.Action)
+		}
+		assert.Equal(t, []rownd.ErasureAction{
+			rownd.ErasureActionRevokeInvite,
+			rownd.ErasureActionTransferOwner,
+			rownd.ErasureActionDeleteGroup,
+			rownd.ErasureActionRemoveMembership,
+			rownd.ErasureActionDeleteUser,
+		}, actions)
+		assert.Equal(t, []string{
+			"DELETE /applications/app_test/groups/group_other/invites/invite_1",
+			"PUT /applications/app_test/groups/group_shared/members/member_peer",
+			"DELETE /applications/app_test/groups/group_solo",
+			"DELETE /applications/app_test/groups/group_shared/members/member_subject",
+			"DELETE /applications/app_test/users/user_subject/data",
+		}, calls)
+	})
+}
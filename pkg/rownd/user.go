@@ -2,7 +2,7 @@ package rownd
 
 import (
 	"context"
-	"net/http"
+	"fmt"
 	"net/url"
 	"strconv"
 	"strings"
@@ -92,15 +92,15 @@ func (c *userClient) Get(ctx context.Context, request GetUserRequest) (*User, er
 		return nil, err
 	}
 
-	endpoint, err := c.rowndURL("applications", c.appID, "users", request.UserID, "data")
+	endpoint, err := c.endpoint(c.endpoints.Users.Get, c.appID, request.UserID)
 	if err != nil {
 		return nil, err
 	}
 
-	endpoint.RawQuery = request.params().Encode()
+	endpoint.URL.RawQuery = request.params().Encode()
 
 	var response *User
-	if err := c.request(ctx, http.MethodGet, endpoint.String(), nil, &response, c.httpClientOpts...); err != nil {
+	if err := c.request(ctx, endpoint, nil, &response); err != nil {
 		return nil, err
 	}
 
@@ -110,6 +110,12 @@ func (c *userClient) Get(ctx context.Context, request GetUserRequest) (*User, er
 	return response, nil
 }
 
+const (
+	maxListUsersPageSize int = 1000
+	// maxIDFilterSize keeps the id_filter query parameter well within URL length limits.
+	maxIDFilterSize int = 100
+)
+
 // ListUsersRequest ...
 type ListUsersRequest struct {
 	// Fields is a comma-separated list of fields to include in the profile data
@@ -119,8 +125,8 @@ type ListUsersRequest struct {
 	// Example: "user@example.com"
 	LookupFilter []string `json:"lookup_filter"`
 
-	// A comma-separated list of resource IDs to filter by.
-	// TODO does this exclude/include the id?
+	// IDFilter restricts the results to users with these IDs. Long lists are best sent through
+	// UserQuery, which splits them across requests.
 	IDFilter []string `json:"id_filter"`
 
 	// Number of resources to return per query. Max is 1000.
@@ -158,6 +164,9 @@ func (r *ListUsersRequest) params() url.Values {
 	if r.Sort != nil {
 		q.Add("sort", string(*r.Sort))
 	}
+	if r.IncludeDuplicates != nil {
+		q.Add("include_duplicates", strconv.FormatBool(*r.IncludeDuplicates))
+	}
 
 	return q
 }
@@ -165,6 +174,13 @@ func (r *ListUsersRequest) params() url.Values {
 func (r *ListUsersRequest) validate() error {
 	var errs []error
 
+	if r.PageSize != nil && (*r.PageSize < 1 || *r.PageSize > maxListUsersPageSize) {
+		errs = append(errs, NewError(ErrValidation, fmt.Sprintf("page size must be between 1 and %d", maxListUsersPageSize), nil))
+	}
+	if r.Sort != nil && *r.Sort != SortAsc && *r.Sort != SortDesc {
+		errs = append(errs, NewError(ErrValidation, fmt.Sprintf("invalid sort %q", *r.Sort), nil))
+	}
+
 	if len(errs) == 0 {
 		return nil
 	}
@@ -186,15 +202,15 @@ func (c *userClient) List(ctx context.Context, request ListUsersRequest) (*ListU
 		return nil, err
 	}
 
-	endpoint, err := c.rowndURL("applications", c.appID, "users", "data")
+	endpoint, err := c.endpoint(c.endpoints.Users.List, c.appID)
 	if err != nil {
 		return nil, err
 	}
 
-	endpoint.RawQuery = request.params().Encode()
+	endpoint.URL.RawQuery = request.params().Encode()
 
 	var response *ListUsersResponse
-	if err := c.request(ctx, http.MethodGet, endpoint.String(), nil, &response, c.httpClientOpts...); err != nil {
+	if err := c.request(ctx, endpoint, nil, &response); err != nil {
 		return nil, err
 	}
 
@@ -208,6 +224,25 @@ func (c *userClient) List(ctx context.Context, request ListUsersRequest) (*ListU
 	return response, nil
 }
 
+// Special user ID values that tell Rownd to generate the ID of a new user.
+const (
+	UserIDDefault  string = "__default__"
+	UserIDUUID     string = "__uuid__"
+	UserIDObjectID string = "__objectid__"
+	UserIDRowndID  string = "__rowndid__"
+)
+
+// isGeneratedUserID reports whether the user ID is one of the special values that ask Rownd to
+// generate an ID.
+func isGeneratedUserID(userID string) bool {
+	switch strings.ToLower(userID) {
+	case UserIDDefault, UserIDUUID, UserIDObjectID, UserIDRowndID:
+		return true
+	default:
+		return false
+	}
+}
+
 // CreateOrUpdateUserRequest represents the request body for updating a user
 type CreateOrUpdateUserRequest struct {
 	// Rownd User id.
@@ -255,20 +290,24 @@ func (c *userClient) CreateOrUpdate(ctx context.Context, request CreateOrUpdateU
 		return nil, err
 	}
 
-	endpoint, err := c.rowndURL("applications", c.appID, "users", request.UserID, "data")
+	endpoint, err := c.endpoint(c.endpoints.Users.CreateOrUpdate, c.appID, request.UserID)
 	if err != nil {
 		return nil, err
 	}
 
-	endpoint.RawQuery = request.params().Encode()
+	endpoint.URL.RawQuery = request.params().Encode()
+	if isGeneratedUserID(request.UserID) {
+		// each request with a generated ID creates a new user, so it must not be repeated
+		endpoint.Retryable = false
+	}
 
 	var response *User
-	if err := c.request(ctx, http.MethodPut, endpoint.String(), request, &response, c.httpClientOpts...); err != nil {
+	if err := c.request(ctx, endpoint, request, &response); err != nil {
 		return nil, err
 	}
 
 	// For new user creation, get the ID from data.user_id
-	if request.UserID == "__UUID__" && response.ID == "" {
+	if isGeneratedUserID(request.UserID) && response.ID == "" {
 		if userID, ok := response.Data["user_id"].(string); ok {
 			response.ID = userID
 		}
@@ -288,6 +327,8 @@ type PatchUserRequest struct {
 	// default: true
 	WriteDataToIntegrations *bool `json:"-"`
 
+	// Data is merged into the top level of the profile data: a nil value removes the field and any
+	// other value, including a nested object, replaces it. See MergePatch, DiffUsers and Unset.
 	Data map[string]any `json:"data"`
 }
 
@@ -321,15 +362,15 @@ func (c *userClient) Patch(ctx context.Context, request PatchUserRequest) (*User
 		return nil, err
 	}
 
-	endpoint, err := c.rowndURL("applications", c.appID, "users", request.UserID, "data")
+	endpoint, err := c.endpoint(c.endpoints.Users.Patch, c.appID, request.UserID)
 	if err != nil {
 		return nil, err
 	}
 
-	endpoint.RawQuery = request.params().Encode()
+	endpoint.URL.RawQuery = request.params().Encode()
 
 	var response *User
-	if err := c.request(ctx, http.MethodPatch, endpoint.String(), request, &response, c.httpClientOpts...); err != nil {
+	if err := c.request(ctx, endpoint, request, &response); err != nil {
 		return nil, err
 	}
 
@@ -363,12 +404,12 @@ func (c *userClient) Delete(ctx context.Context, request DeleteUserRequest) erro
 		return err
 	}
 
-	endpoint, err := c.rowndURL("applications", c.appID, "users", request.UserID, "data")
+	endpoint, err := c.endpoint(c.endpoints.Users.Delete, c.appID, request.UserID)
 	if err != nil {
 		return err
 	}
 
-	if err := c.request(ctx, http.MethodDelete, endpoint.String(), nil, nil, c.httpClientOpts...); err != nil {
+	if err := c.request(ctx, endpoint, nil, nil); err != nil {
 		return err
 	}
 
@@ -0,0 +1,462 @@
+package rownd
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// FindDuplicatesOptions configures a duplicate user scan.
+type FindDuplicatesOptions struct {
+	// Fields are the profile fields compared between users. Defaults to email and phone.
+	Fields []string
+
+	// Request holds filters applied while paging through users.
+	Request ListUsersRequest
+}
+
+// DuplicateGroup is a set of users that share at least one value of the compared fields, directly
+// or through another user in the group.
+type DuplicateGroup struct {
+	// Matches lists the shared values as "field=value".
+	Matches []string
+	Users   []User
+}
+
+// normalizeLookupValue makes comparisons insensitive to case and surrounding whitespace.
+func normalizeLookupValue(v any) string {
+	s, ok := v.(string)
+	if !ok {
+		if v == nil {
+			return ""
+		}
+		s = fmt.Sprint(v)
+	}
+	return strings.ToLower(strings.TrimSpace(s))
+}
+
+// FindDuplicates pages through users and groups those sharing a value of any of the compared
+// fields. All scanned users are held in memory while the groups are computed.
+func (c *userClient) FindDuplicates(ctx context.Context, opts FindDuplicatesOptions) ([]DuplicateGroup, error) {
+	fields := opts.Fields
+	if len(fields) == 0 {
+		fields = []string{"email", "phone"}
+	}
+
+	request := opts.Request
+	if request.PageSize == nil {
+		request.PageSize = ToPointer(maxListUsersPageSize)
+	}
+
+	var (
+		users   []User
+		parent  []int
+		byValue = map[string]int{}
+		matches = map[string][]int{}
+	)
+	find := func(i int) int {
+		for parent[i] != i {
+			parent[i] = parent[parent[i]]
+			i = parent[i]
+		}
+		return i
+	}
+
+	err := c.eachPage(ctx, request, func(page []User) error {
+		for _, u := range page {
+			i := len(users)
+			users = append(users, u)
+			parent = append(parent, i)
+
+			for _, field := range fields {
+				value := normalizeLookupValue(u.Data[field])
+				if value == "" {
+					continue
+				}
+
+				key := field + "=" + value
+				if j, ok := byValue[key]; ok {
+					parent[find(i)] = find(j)
+					matches[key] = append(matches[key], i)
+				} else {
+					byValue[key] = i
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	groups := map[int]*DuplicateGroup{}
+	for key, indexes := range matches {
+		root := find(indexes[0])
+		g, ok := groups[root]
+		if !ok {
+			g = &DuplicateGroup{}
+			groups[root] = g
+		}
+		g.Matches = append(g.Matches, key)
+	}
+	for i := range users {
+		if g, ok := groups[find(i)]; ok {
+			g.Users = append(g.Users, users[i])
+		}
+	}
+
+	result := make([]DuplicateGroup, 0, len(groups))
+	for _, g := range groups {
+		sort.Strings(g.Matches)
+		result = append(result, *g)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Users[0].GetID() < result[j].Users[0].GetID()
+	})
+
+	return result, nil
+}
+
+// MergeCandidate is one user's value for a field that differs between the users being merged.
+type MergeCandidate struct {
+	User     *User
+	Value    any
+	Verified bool
+}
+
+// MergeStrategy picks the merged value of a conflicting field. Candidates always start with the
+// primary user when it has a value, followed by duplicates in the order they were passed to Merge.
+type MergeStrategy func(field string, candidates []MergeCandidate) any
+
+// MergePreferPrimary keeps the primary user's value, falling back to the first duplicate.
+func MergePreferPrimary(_ string, candidates []MergeCandidate) any {
+	return candidates[0].Value
+}
+
+// MergePreferNewest takes the value of the most recently modified user.
+func MergePreferNewest(_ string, candidates []MergeCandidate) any {
+	newest := candidates[0]
+	for _, c := range candidates[1:] {
+		if c.User.Meta.Modified.After(newest.User.Meta.Modified) {
+			newest = c
+		}
+	}
+	return newest.Value
+}
+
+// MergePreferVerified takes the first verified value, falling back to the newest value.
+func MergePreferVerified(field string, candidates []MergeCandidate) any {
+	for _, c := range candidates {
+		if c.Verified {
+			return c.Value
+		}
+	}
+	return MergePreferNewest(field, candidates)
+}
+
+// MergeOptions configures a merge.
+type MergeOptions struct {
+	// Strategy resolves conflicting fields. Defaults to MergePreferNewest.
+	Strategy MergeStrategy
+
+	// DryRun computes the merge without changing anything.
+	DryRun bool
+}
+
+// FieldChange is a profile field of the primary user changed by a merge.
+type FieldChange struct {
+	Field string `json:"field"`
+	From  any    `json:"from"`
+	To    any    `json:"to"`
+}
+
+// MembershipChange is a group membership of the primary user added or updated by a merge, or a
+// membership of a duplicate removed from a group where the primary already holds its roles.
+type MembershipChange struct {
+	GroupID string  `json:"group_id"`
+	Action  string  `json:"action"` // "add", "update_roles" or "remove_duplicate"
+	Roles   RoleSet `json:"roles"`
+	// FromUserID is the duplicate the membership was moved from.
+	FromUserID string `json:"from_user_id"`
+}
+
+// MergeResult describes what a merge changed, or would change in a dry run.
+type MergeResult struct {
+	DryRun            bool               `json:"dry_run"`
+	PrimaryID         string             `json:"primary_id"`
+	Data              map[string]any     `json:"data"`
+	DataChanges       []FieldChange      `json:"data_changes"`
+	MembershipChanges []MembershipChange `json:"membership_changes"`
+	DeletedUserIDs    []string           `json:"deleted_user_ids"`
+}
+
+// Merge combines duplicate users into the primary user. Conflicting profile fields are resolved
+// with the strategy, group memberships of the duplicates are moved to the primary, and the
+// duplicates are deleted. The primary's profile is patched before any duplicate is deleted, so a
+// failed patch never leaves the merged data without a copy.
+func (c *userClient) Merge(ctx context.Context, primaryID string, duplicateIDs []string, opts MergeOptions) (*MergeResult, error) {
+	var errs []error
+	if primaryID == "" {
+		errs = append(errs, NewError(ErrValidation, "primary user id is required", nil))
+	}
+	if len(duplicateIDs) == 0 {
+		errs = append(errs, NewError(ErrValidation, "at least one duplicate user id is required", nil))
+	}
+	for _, id := range duplicateIDs {
+		if id == "" || id == primaryID {
+			errs = append(errs, NewError(ErrValidation, fmt.Sprintf("invalid duplicate user id %q", id), nil))
+		}
+	}
+	if len(errs) > 0 {
+		return nil, &MultiError{errors: errs}
+	}
+
+	strategy := opts.Strategy
+	if strategy == nil {
+		strategy = MergePreferNewest
+	}
+
+	primary, err := c.Get(ctx, GetUserRequest{UserID: primaryID})
+	if err != nil {
+		return nil, err
+	}
+	duplicates := make([]*User, 0, len(duplicateIDs))
+	for _, id := range duplicateIDs {
+		u, err := c.Get(ctx, GetUserRequest{UserID: id})
+		if err != nil {
+			return nil, err
+		}
+		duplicates = append(duplicates, u)
+	}
+
+	result := &MergeResult{
+		DryRun:            opts.DryRun,
+		PrimaryID:         primaryID,
+		Data:              mergeUserData(primary, duplicates, strategy),
+		DataChanges:       []FieldChange{},
+		MembershipChanges: []MembershipChange{},
+		DeletedUserIDs:    []string{},
+	}
+	for field, value := range result.Data {
+		if from, ok := primary.Data[field]; !ok || !reflect.DeepEqual(from, value) {
+			result.DataChanges = append(result.DataChanges, FieldChange{Field: field, From: from, To: value})
+		}
+	}
+	sort.Slice(result.DataChanges, func(i, j int) bool {
+		return result.DataChanges[i].Field < result.DataChanges[j].Field
+	})
+
+	memberships, err := c.membershipsByUser(ctx, append([]string{primaryID}, duplicateIDs...))
+	if err != nil {
+		return nil, err
+	}
+	moves := planMembershipMoves(primaryID, duplicateIDs, memberships)
+	for _, m := range moves {
+		result.MembershipChanges = append(result.MembershipChanges, m.change)
+	}
+
+	if opts.DryRun {
+		result.DeletedUserIDs = append(result.DeletedUserIDs, duplicateIDs...)
+		return result, nil
+	}
+
+	for _, m := range moves {
+		if err := c.applyMembershipMove(ctx, primaryID, m); err != nil {
+			return result, err
+		}
+	}
+
+	if len(result.DataChanges) > 0 {
+		patch := make(map[string]any, len(result.DataChanges))
+		for _, change := range result.DataChanges {
+			patch[change.Field] = change.To
+		}
+		if _, err := c.Patch(ctx, PatchUserRequest{UserID: primaryID, Data: patch}); err != nil {
+			return result, err
+		}
+	}
+
+	for _, id := range duplicateIDs {
+		if err := c.Delete(ctx, DeleteUserRequest{UserID: id}); err != nil {
+			return result, err
+		}
+		result.DeletedUserIDs = append(result.DeletedUserIDs, id)
+	}
+
+	return result, nil
+}
+
+func mergeUserData(primary *User, duplicates []*User, strategy MergeStrategy) map[string]any {
+	users := append([]*User{primary}, duplicates...)
+
+	fields := map[string]bool{}
+	for _, u := range users {
+		for field := range u.Data {
+			fields[field] = true
+		}
+	}
+	delete(fields, "user_id")
+
+	merged := make(map[string]any, len(fields))
+	for field := range fields {
+		var candidates []MergeCandidate
+		for _, u := range users {
+			value, ok := u.Data[field]
+			if !ok || value == nil || value == "" {
+				continue
+			}
+			verified, ok := u.VerifiedData[field]
+			candidates = append(candidates, MergeCandidate{
+				User:     u,
+				Value:    value,
+				Verified: ok && reflect.DeepEqual(verified, value),
+			})
+		}
+		if len(candidates) == 0 {
+			continue
+		}
+
+		conflict := false
+		for _, c := range candidates[1:] {
+			if !reflect.DeepEqual(c.Value, candidates[0].Value) {
+				conflict = true
+				break
+			}
+		}
+		if conflict {
+			merged[field] = strategy(field, candidates)
+		} else {
+			merged[field] = candidates[0].Value
+		}
+	}
+
+	return merged
+}
+
+// membershipsByUser scans every group once for memberships of the supplied users.
+func (c *userClient) membershipsByUser(ctx context.Context, userIDs []string) (map[string][]SubjectGroupMembership, error) {
+	wanted := make(map[string]bool, len(userIDs))
+	for _, id := range userIDs {
+		wanted[id] = true
+	}
+
+	result := map[string][]SubjectGroupMembership{}
+	err := c.Groups.eachGroup(ctx, func(group Group) error {
+		return c.GroupMembers.eachMember(ctx, group.ID, func(member GroupMember) error {
+			if wanted[member.UserID] {
+				result[member.UserID] = append(result[member.UserID], SubjectGroupMembership{Group: group, Member: member})
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+type membershipMove struct {
+	change     MembershipChange
+	primary    *GroupMember
+	duplicates []GroupMember
+}
+
+// planMembershipMoves gives the primary every group membership of the duplicates, merging roles
+// where the primary is already a member. Duplicates sharing a group the primary is not in are
+// folded into a single add.
+func planMembershipMoves(primaryID string, duplicateIDs []string, memberships map[string][]SubjectGroupMembership) []membershipMove {
+	primaryByGroup := map[string]*GroupMember{}
+	for _, m := range memberships[primaryID] {
+		member := m.Member
+		primaryByGroup[m.Group.ID] = &member
+	}
+
+	var moves []membershipMove
+	adds := map[string]int{}
+	for _, id := range duplicateIDs {
+		for _, m := range memberships[id] {
+			groupID := m.Group.ID
+			if i, ok := adds[groupID]; ok {
+				moves[i].change.Roles = moves[i].change.Roles.Add(m.Member.Roles...)
+				moves[i].duplicates = append(moves[i].duplicates, m.Member)
+				continue
+			}
+
+			existing, ok := primaryByGroup[groupID]
+			if !ok {
+				adds[groupID] = len(moves)
+				moves = append(moves, membershipMove{
+					change:     MembershipChange{GroupID: groupID, Action: "add", Roles: RoleSet{}.Add(m.Member.Roles...), FromUserID: id},
+					duplicates: []GroupMember{m.Member},
+				})
+				continue
+			}
+
+			roles := existing.Roles.Add(m.Member.Roles...)
+			if len(roles) == len(existing.Roles) {
+				moves = append(moves, membershipMove{
+					change:     MembershipChange{GroupID: groupID, Action: "remove_duplicate", Roles: roles, FromUserID: id},
+					primary:    existing,
+					duplicates: []GroupMember{m.Member},
+				})
+				continue
+			}
+			existing.Roles = roles
+			moves = append(moves, membershipMove{
+				change:     MembershipChange{GroupID: groupID, Action: "update_roles", Roles: roles, FromUserID: id},
+				primary:    existing,
+				duplicates: []GroupMember{m.Member},
+			})
+		}
+	}
+
+	return moves
+}
+
+// applyMembershipMove grants the primary the membership before the duplicate's membership is
+// removed, so a group never loses its owner. A new membership takes the duplicates' state, active
+// if any of them is.
+func (c *userClient) applyMembershipMove(ctx context.Context, primaryID string, m membershipMove) error {
+	groupID := m.change.GroupID
+
+	switch {
+	case m.change.Action == "remove_duplicate":
+	case m.primary == nil:
+		state := m.duplicates[0].State
+		for _, d := range m.duplicates {
+			if d.State.isActive() {
+				state = d.State
+				break
+			}
+		}
+		if _, err := c.GroupMembers.Create(ctx, CreateGroupMemberRequest{
+			GroupID: groupID,
+			UserID:  primaryID,
+			Roles:   m.change.Roles,
+			State:   state,
+		}); err != nil {
+			return fmt.Errorf("failed to add user %s to group %s: %w", primaryID, groupID, err)
+		}
+	default:
+		if _, err := c.GroupMembers.Update(ctx, UpdateGroupMemberRequest{
+			GroupID:  groupID,
+			MemberID: m.primary.ID,
+			UserID:   primaryID,
+			Roles:    m.change.Roles,
+			State:    m.primary.State,
+		}); err != nil {
+			return fmt.Errorf("failed to update roles of user %s in group %s: %w", primaryID, groupID, err)
+		}
+	}
+
+	for _, d := range m.duplicates {
+		if err := c.GroupMembers.Delete(ctx, DeleteGroupMemberRequest{GroupID: groupID, MemberID: d.ID}); err != nil {
+			return fmt.Errorf("failed to remove user %s from group %s: %w", d.UserID, groupID, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,180 @@
+package rownd_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/rownd/client-go/pkg/rownd"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindDuplicates(t *testing.T) {
+	users := []map[string]any{
+		{"rownd_user": "user_a", "data": map[string]any{"email": "Jane@Example.com"}},
+		{"rownd_user": "user_b", "data": map[string]any{"email": "jane@example.com ", "phone": "+15555550100"}},
+		{"rownd_user": "user_c", "data": map[string]any{"phone": "+15555550100"}},
+		{"rownd_user": "user_d", "data": map[string]any{"email": "other@example.com"}},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/applications/", fakeUsers(users))
+	client := newTestClient(t, mux)
+
+	groups, err := client.Users.FindDuplicates(context.Background(), rownd.FindDuplicatesOptions{})
+	assert.NoError(t, err)
+	if assert.Len(t, groups, 1) {
+		assert.Equal(t, []string{"email=jane@example.com", "phone=+15555550100"}, groups[0].Matches)
+		var ids []string
+		for _, u := range groups[0].Users {
+			ids = append(ids, u.ID)
+		}
+		assert.Equal(t, []string{"user_a", "user_b", "user_c"}, ids)
+	}
+
+	groups, err = client.Users.FindDuplicates(context.Background(), rownd.FindDuplicatesOptions{Fields: []string{"phone"}})
+	assert.NoError(t, err)
+	if assert.Len(t, groups, 1) {
+		assert.Len(t, groups[0].Users, 2)
+	}
+}
+
+func TestMergeUsers(t *testing.T) {
+	var (
+		mu     sync.Mutex
+		calls  []string
+		patch  map[string]any
+		posted = map[string]map[string]any{}
+	)
+
+	users := map[string]map[string]any{
+		"user_primary": {
+			"rownd_user":    "user_primary",
+			"data":          map[string]any{"email": "jane@example.com", "first_name": "Jane"},
+			"verified_data": map[string]any{"email": "jane@example.com"},
+			"meta":          map[string]any{"modified": "2024-01-01T00:00:00Z"},
+		},
+		"user_dup": {
+			"rownd_user": "user_dup",
+			"data":       map[string]any{"email": "jane@work.com", "first_name": "Janet", "phone": "+15555550100"},
+			"meta":       map[string]any{"modified": "2024-06-01T00:00:00Z"},
+		},
+		"user_dup2": {
+			"rownd_user": "user_dup2",
+			"data":       map[string]any{"email": "jane@example.com"},
+		},
+	}
+	groups := []map[string]any{{"id": "group_shared"}, {"id": "group_dup"}, {"id": "group_suspended"}}
+	members := map[string][]map[string]any{
+		"group_shared": {
+			{"id": "member_p", "user_id": "user_primary", "roles": []string{"member"}, "state": "active"},
+			{"id": "member_d1", "user_id": "user_dup", "roles": []string{"owner"}, "state": "active"},
+			{"id": "member_d4", "user_id": "user_dup2", "roles": []string{"member"}, "state": "active"},
+		},
+		"group_dup": {
+			{"id": "member_d2", "user_id": "user_dup", "roles": []string{"member"}, "state": "active"},
+			{"id": "member_d3", "user_id": "user_dup2", "roles": []string{"admin"}, "state": "active"},
+		},
+		"group_suspended": {
+			{"id": "member_d5", "user_id": "user_dup", "roles": []string{"member"}, "state": "suspended"},
+		},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/applications/", func(w http.ResponseWriter, r *http.Request) {
+		segments := pathSegments(r)
+		mu.Lock()
+		defer mu.Unlock()
+
+		if r.Method != http.MethodGet {
+			calls = append(calls, r.Method+" "+r.URL.Path)
+		}
+
+		switch {
+		case segments[2] == "users" && r.Method == http.MethodGet:
+			writeJSON(w, http.StatusOK, users[segments[3]])
+		case segments[2] == "users" && r.Method == http.MethodPatch:
+			json.NewDecoder(r.Body).Decode(&patch)
+			writeJSON(w, http.StatusOK, users[segments[3]])
+		case segments[2] == "users":
+			w.WriteHeader(http.StatusNoContent)
+		case len(segments) == 3 && r.Method == http.MethodGet:
+			writeJSON(w, http.StatusOK, map[string]any{"total_results": len(groups), "results": groups})
+		case len(segments) == 5 && r.Method == http.MethodGet:
+			writeJSON(w, http.StatusOK, map[string]any{"results": members[segments[3]]})
+		case len(segments) == 6 && r.Method == http.MethodGet:
+			writeMember(w, members, r)
+		case r.Method == http.MethodPost || r.Method == http.MethodPut:
+			var body map[string]any
+			json.NewDecoder(r.Body).Decode(&body)
+			if r.Method == http.MethodPost {
+				posted[segments[3]] = body
+			}
+			applyMemberWrite(members, r, body)
+			writeJSON(w, http.StatusOK, body)
+		default:
+			applyMemberWrite(members, r, nil)
+			w.WriteHeader(http.StatusNoContent)
+		}
+	})
+
+	client := newTestClient(t, mux)
+	ctx := context.Background()
+
+	t.Run("validation", func(t *testing.T) {
+		_, err := client.Users.Merge(ctx, "user_primary", []string{"user_primary"}, rownd.MergeOptions{})
+		assert.Error(t, err)
+	})
+
+	t.Run("dry run prefers verified", func(t *testing.T) {
+		result, err := client.Users.Merge(ctx, "user_primary", []string{"user_dup", "user_dup2"}, rownd.MergeOptions{
+			Strategy: rownd.MergePreferVerified,
+			DryRun:   true,
+		})
+		assert.NoError(t, err)
+		assert.Empty(t, calls, "dry run must not modify anything")
+
+		assert.Equal(t, "jane@example.com", result.Data["email"])
+		assert.Equal(t, "Janet", result.Data["first_name"], "falls back to newest")
+		assert.Equal(t, []rownd.FieldChange{
+			{Field: "first_name", From: "Jane", To: "Janet"},
+			{Field: "phone", To: "+15555550100"},
+		}, result.DataChanges)
+		assert.ElementsMatch(t, []rownd.MembershipChange{
+			{GroupID: "group_shared", Action: "update_roles", Roles: rownd.RoleSet{"member", "owner"}, FromUserID: "user_dup"},
+			{GroupID: "group_dup", Action: "add", Roles: rownd.RoleSet{"member", "admin"}, FromUserID: "user_dup"},
+			{GroupID: "group_suspended", Action: "add", Roles: rownd.RoleSet{"member"}, FromUserID: "user_dup"},
+			{GroupID: "group_shared", Action: "remove_duplicate", Roles: rownd.RoleSet{"member", "owner"}, FromUserID: "user_dup2"},
+		}, result.MembershipChanges, "roles of duplicates in the same group are folded into one add")
+		assert.Equal(t, []string{"user_dup", "user_dup2"}, result.DeletedUserIDs)
+	})
+
+	t.Run("merge", func(t *testing.T) {
+		custom := func(field string, candidates []rownd.MergeCandidate) any {
+			return candidates[0].Value
+		}
+		result, err := client.Users.Merge(ctx, "user_primary", []string{"user_dup", "user_dup2"}, rownd.MergeOptions{Strategy: custom})
+		assert.NoError(t, err)
+		assert.False(t, result.DryRun)
+
+		assert.Equal(t, []string{
+			"PUT /applications/app_test/groups/group_shared/members/member_p",
+			"DELETE /applications/app_test/groups/group_shared/members/member_d1",
+			"POST /applications/app_test/groups/group_dup/members",
+			"DELETE /applications/app_test/groups/group_dup/members/member_d2",
+			"DELETE /applications/app_test/groups/group_dup/members/member_d3",
+			"POST /applications/app_test/groups/group_suspended/members",
+			"DELETE /applications/app_test/groups/group_suspended/members/member_d5",
+			"DELETE /applications/app_test/groups/group_shared/members/member_d4",
+			"PATCH /applications/app_test/users/user_primary/data",
+			"DELETE /applications/app_test/users/user_dup/data",
+			"DELETE /applications/app_test/users/user_dup2/data",
+		}, calls, "the primary is patched before the duplicates are deleted")
+		assert.ElementsMatch(t, []any{"member", "admin"}, posted["group_dup"]["roles"])
+		assert.Equal(t, "active", posted["group_dup"]["state"])
+		assert.Equal(t, "suspended", posted["group_suspended"]["state"], "the duplicate's state is kept")
+		assert.Equal(t, map[string]any{"phone": "+15555550100"}, patch["data"])
+	})
+}
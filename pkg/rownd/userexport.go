@@ -0,0 +1,333 @@
+package rownd
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ExportFormat is the output format of a user export.
+type ExportFormat string
+
+const (
+	ExportFormatJSONLines ExportFormat = "jsonl"
+	ExportFormatCSV       ExportFormat = "csv"
+)
+
+func (f ExportFormat) validate() bool {
+	switch f {
+	case ExportFormatJSONLines, ExportFormatCSV:
+		return true
+	default:
+		return false
+	}
+}
+
+// ExportOptions configures a user export.
+type ExportOptions struct {
+	// Format is the output format. Defaults to JSON Lines.
+	Format ExportFormat
+
+	// Request holds the fields selection and filters applied to every page. PageSize defaults to
+	// the maximum of 1000 and After may be set to resume an export.
+	Request ListUsersRequest
+
+	// IncludeMeta adds the user's sign-in and modification timestamps.
+	IncludeMeta bool
+
+	// IncludeGroups adds the user's group memberships. Users listed without their memberships are
+	// looked up in every group, which takes a request per group and user unless the membership
+	// cache is enabled.
+	IncludeGroups bool
+}
+
+func (o ExportOptions) validate() error {
+	var errs []error
+
+	if o.Format != "" && !o.Format.validate() {
+		errs = append(errs, NewError(ErrValidation, "invalid export format", nil))
+	}
+	if o.Format == ExportFormatCSV && len(o.Request.Fields) == 0 {
+		errs = append(errs, NewError(ErrValidation, "fields are required for CSV exports", nil))
+	}
+	if err := o.Request.validate(); err != nil {
+		errs = append(errs, err)
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+
+	return &MultiError{errors: errs}
+}
+
+// exportRecord is the JSON Lines representation of a user.
+type exportRecord struct {
+	ID           string                  `json:"id"`
+	State        string                  `json:"state"`
+	AuthLevel    AuthLevel               `json:"auth_level"`
+	Data         map[string]any          `json:"data"`
+	VerifiedData map[string]any          `json:"verified_data"`
+	Meta         *UserMeta               `json:"meta,omitempty"`
+	Groups       []exportGroupMembership `json:"groups,omitempty"`
+}
+
+type exportGroupMembership struct {
+	GroupID   string  `json:"group_id"`
+	GroupName string  `json:"group_name"`
+	MemberID  string  `json:"member_id"`
+	Roles     RoleSet `json:"roles"`
+}
+
+// userExporter writes users in the selected format.
+type userExporter interface {
+	write(users []User) error
+	flush() error
+}
+
+// Export streams every user matching the options to w, one page at a time, and returns the number
+// of users written. Memory use is bounded by the page size regardless of the number of users.
+//
+// CSV exports flatten profile data into "data.<field>" and "verified_data.<field>" columns, one per
+// entry in Request.Fields, which is required for CSV.
+func (c *userClient) Export(ctx context.Context, w io.Writer, opts ExportOptions) (int, error) {
+	if err := opts.validate(); err != nil {
+		return 0, err
+	}
+
+	request := opts.Request
+	if request.PageSize == nil {
+		request.PageSize = ToPointer(maxListUsersPageSize)
+	}
+
+	var exporter userExporter
+	switch opts.Format {
+	case ExportFormatCSV:
+		exporter = &csvUserExporter{w: csv.NewWriter(w), opts: opts}
+	default:
+		exporter = &jsonLinesUserExporter{enc: json.NewEncoder(w), opts: opts}
+	}
+
+	var groups []Group
+	if opts.IncludeGroups {
+		err := c.Groups.eachGroup(ctx, func(group Group) error {
+			groups = append(groups, group)
+			return nil
+		})
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	count := 0
+	err := c.eachPage(ctx, request, func(users []User) error {
+		if opts.IncludeGroups {
+			for i := range users {
+				if len(users[i].Groups) > 0 {
+					continue
+				}
+				memberships, err := c.Groups.membershipsIn(ctx, groups, users[i].GetID())
+				if err != nil {
+					return err
+				}
+				users[i].Groups = memberships
+			}
+		}
+		if err := exporter.write(users); err != nil {
+			return fmt.Errorf("failed to write users: %w", err)
+		}
+		count += len(users)
+		return nil
+	})
+	if err != nil {
+		return count, err
+	}
+
+	return count, exporter.flush()
+}
+
+// eachPage calls fn with every page of users matching the request until the last page is reached.
+func (c *userClient) eachPage(ctx context.Context, request ListUsersRequest, fn func(users []User) error) error {
+	for {
+		page, err := c.List(ctx, request)
+		if err != nil {
+			return err
+		}
+		if len(page.Results) == 0 {
+			return nil
+		}
+		if err := fn(page.Results); err != nil {
+			return err
+		}
+		if request.PageSize != nil && len(page.Results) < *request.PageSize {
+			return nil
+		}
+
+		last := page.Results[len(page.Results)-1].GetID()
+		if last == "" || (request.After != nil && *request.After == last) {
+			return nil
+		}
+		request.After = ToPointer(last)
+	}
+}
+
+type jsonLinesUserExporter struct {
+	enc  *json.Encoder
+	opts ExportOptions
+}
+
+func (e *jsonLinesUserExporter) write(users []User) error {
+	for _, u := range users {
+		record := exportRecord{
+			ID:           u.GetID(),
+			State:        u.State,
+			AuthLevel:    u.AuthLevel,
+			Data:         u.Data,
+			VerifiedData: u.VerifiedData,
+		}
+		if e.opts.IncludeMeta {
+			record.Meta = ToPointer(u.Meta)
+		}
+		if e.opts.IncludeGroups {
+			for _, m := range u.Groups {
+				record.Groups = append(record.Groups, exportGroupMembership{
+					GroupID:   m.Group.ID,
+					GroupName: m.Group.Name,
+					MemberID:  m.Member.ID,
+					Roles:     m.Member.Roles,
+				})
+			}
+		}
+
+		if err := e.enc.Encode(record); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (e *jsonLinesUserExporter) flush() error {
+	return nil
+}
+
+type csvUserExporter struct {
+	w             *csv.Writer
+	opts          ExportOptions
+	dataFields    []string
+	headerWritten bool
+}
+
+var exportMetaColumns = []string{
+	"meta.created",
+	"meta.modified",
+	"meta.first_sign_in",
+	"meta.last_sign_in",
+	"meta.last_active",
+}
+
+func (e *csvUserExporter) writeHeader() error {
+	e.dataFields = e.opts.Request.Fields
+
+	header := []string{"id", "state", "auth_level"}
+	for _, f := range e.dataFields {
+		header = append(header, "data."+f)
+	}
+	for _, f := range e.dataFields {
+		header = append(header, "verified_data."+f)
+	}
+	if e.opts.IncludeMeta {
+		header = append(header, exportMetaColumns...)
+	}
+	if e.opts.IncludeGroups {
+		header = append(header, "groups")
+	}
+
+	e.headerWritten = true
+	return e.w.Write(header)
+}
+
+func (e *csvUserExporter) write(users []User) error {
+	if !e.headerWritten {
+		if err := e.writeHeader(); err != nil {
+			return err
+		}
+	}
+
+	for _, u := range users {
+		row := []string{u.GetID(), u.State, string(u.AuthLevel)}
+		for _, f := range e.dataFields {
+			row = append(row, csvValue(u.Data[f]))
+		}
+		for _, f := range e.dataFields {
+			row = append(row, csvValue(u.VerifiedData[f]))
+		}
+		if e.opts.IncludeMeta {
+			row = append(row,
+				csvTime(u.Meta.Created),
+				csvTime(u.Meta.Modified),
+				csvTime(u.Meta.FirstSignIn),
+				csvTime(u.Meta.LastSignIn),
+				csvTime(u.Meta.LastActive),
+			)
+		}
+		if e.opts.IncludeGroups {
+			groupIDs := make([]string, 0, len(u.Groups))
+			for _, m := range u.Groups {
+				groupIDs = append(groupIDs, m.Group.ID)
+			}
+			row = append(row, strings.Join(groupIDs, ";"))
+		}
+
+		if err := e.w.Write(row); err != nil {
+			return err
+		}
+	}
+
+	// flush every page so memory does not grow with the number of users.
+	e.w.Flush()
+	return e.w.Error()
+}
+
+func (e *csvUserExporter) flush() error {
+	if !e.headerWritten {
+		if err := e.writeHeader(); err != nil {
+			return err
+		}
+	}
+
+	e.w.Flush()
+	return e.w.Error()
+}
+
+// csvValue renders a profile value as a CSV cell. Nested values are JSON encoded.
+func csvValue(v any) string {
+	switch t := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return t
+	case bool:
+		return strconv.FormatBool(t)
+	case float64:
+		return strconv.FormatFloat(t, 'f', -1, 64)
+	default:
+		b, err := json.Marshal(t)
+		if err != nil {
+			return fmt.Sprint(t)
+		}
+		return string(b)
+	}
+}
+
+func csvTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,166 @@
+package rownd_test
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/rownd/client-go/pkg/rownd"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeUsers serves paginated user listings.
+func fakeUsers(users []map[string]any) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		pageSize, _ := strconv.Atoi(q.Get("page_size"))
+		if pageSize == 0 {
+			pageSize = len(users)
+		}
+
+		start := 0
+		if after := q.Get("after"); after != "" {
+			for i, u := range users {
+				if u["rownd_user"] == after {
+					start = i + 1
+				}
+			}
+		}
+		end := min(start+pageSize, len(users))
+
+		writeJSON(w, http.StatusOK, map[string]any{
+			"total_results": len(users),
+			"results":       users[start:end],
+		})
+	}
+}
+
+func TestUserExport(t *testing.T) {
+	var users []map[string]any
+	for i := 0; i < 5; i++ {
+		users = append(users, map[string]any{
+			"rownd_user": fmt.Sprintf("user_%d", i),
+			"state":      "enabled",
+			"auth_level": "verified",
+			"data": map[string]any{
+				"email":   fmt.Sprintf("user%d@example.com", i),
+				"address": map[string]any{"city": "Denver"},
+			},
+			"verified_data": map[string]any{"email": fmt.Sprintf("user%d@example.com", i)},
+			"meta":          map[string]any{"created": "2024-03-01T12:00:00Z"},
+		})
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/applications/", fakeUsers(users))
+	client := newTestClient(t, mux)
+	ctx := context.Background()
+
+	t.Run("json lines", func(t *testing.T) {
+		var buf bytes.Buffer
+		n, err := client.Users.Export(ctx, &buf, rownd.ExportOptions{
+			Request: rownd.ListUsersRequest{PageSize: rownd.ToPointer(2)},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 5, n)
+
+		scanner := bufio.NewScanner(&buf)
+		lines := 0
+		for scanner.Scan() {
+			var record map[string]any
+			assert.NoError(t, json.Unmarshal(scanner.Bytes(), &record))
+			assert.Equal(t, fmt.Sprintf("user_%d", lines), record["id"])
+			assert.NotContains(t, record, "meta")
+			lines++
+		}
+		assert.Equal(t, 5, lines)
+	})
+
+	t.Run("csv", func(t *testing.T) {
+		var buf bytes.Buffer
+		n, err := client.Users.Export(ctx, &buf, rownd.ExportOptions{
+			Format:      rownd.ExportFormatCSV,
+			Request:     rownd.ListUsersRequest{PageSize: rownd.ToPointer(3), Fields: []string{"address", "email"}},
+			IncludeMeta: true,
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 5, n)
+
+		rows, err := csv.NewReader(&buf).ReadAll()
+		assert.NoError(t, err)
+		assert.Len(t, rows, 6)
+		assert.Equal(t, []string{
+			"id", "state", "auth_level",
+			"data.address", "data.email",
+			"verified_data.address", "verified_data.email",
+			"meta.created", "meta.modified", "meta.first_sign_in", "meta.last_sign_in", "meta.last_active",
+		}, rows[0])
+		assert.Equal(t, []string{
+			"user_0", "enabled", "verified",
+			`{"city":"Denver"}`, "user0@example.com",
+			"", "user0@example.com",
+			"2024-03-01T12:00:00Z", "", "", "", "",
+		}, rows[1])
+	})
+
+	t.Run("groups", func(t *testing.T) {
+		memberRequests := 0
+		mux := http.NewServeMux()
+		mux.HandleFunc("/applications/app_test/users/", fakeUsers(users[:3]))
+		mux.HandleFunc("/applications/app_test/groups", func(w http.ResponseWriter, r *http.Request) {
+			writeJSON(w, http.StatusOK, map[string]any{"results": []map[string]any{
+				{"id": "group_a", "name": "A"},
+				{"id": "group_b", "name": "B"},
+			}})
+		})
+		mux.HandleFunc("/applications/app_test/groups/", func(w http.ResponseWriter, r *http.Request) {
+			memberRequests++
+			members := map[string][]map[string]any{
+				"group_a": {
+					{"id": "member_1", "user_id": "user_0", "roles": []string{"owner"}},
+					{"id": "member_2", "user_id": "user_1", "roles": []string{"member"}},
+				},
+				"group_b": {
+					{"id": "member_3", "user_id": "user_0", "roles": []string{"member"}},
+				},
+			}
+			writeJSON(w, http.StatusOK, map[string]any{"results": members[pathSegments(r)[3]]})
+		})
+		client := newTestClient(t, mux, rownd.WithMembershipCache(time.Minute))
+
+		var buf bytes.Buffer
+		n, err := client.Users.Export(ctx, &buf, rownd.ExportOptions{
+			Format:        rownd.ExportFormatCSV,
+			Request:       rownd.ListUsersRequest{Fields: []string{"email"}},
+			IncludeGroups: true,
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 3, n)
+		assert.Equal(t, 2, memberRequests, "member lists are fetched once per group")
+
+		rows, err := csv.NewReader(&buf).ReadAll()
+		assert.NoError(t, err)
+		assert.Equal(t, "groups", rows[0][len(rows[0])-1])
+		assert.Equal(t, "group_a;group_b", rows[1][len(rows[1])-1])
+		assert.Equal(t, "group_a", rows[2][len(rows[2])-1])
+		assert.Equal(t, "", rows[3][len(rows[3])-1])
+	})
+
+	t.Run("csv without fields", func(t *testing.T) {
+		_, err := client.Users.Export(ctx, &bytes.Buffer{}, rownd.ExportOptions{Format: rownd.ExportFormatCSV})
+		assert.Error(t, err)
+		assert.Equal(t, rownd.ErrValidation, rownd.KindOf(err))
+	})
+
+	t.Run("invalid format", func(t *testing.T) {
+		_, err := client.Users.Export(ctx, &bytes.Buffer{}, rownd.ExportOptions{Format: "xml"})
+		assert.Error(t, err)
+	})
+}
@@ -3,9 +3,9 @@ package rownd
 import (
 	"context"
 	"fmt"
-	"net/http"
 	"net/url"
 	"strconv"
+	"sync"
 )
 
 type userFieldClient struct {
@@ -57,15 +57,15 @@ func (c *userFieldClient) Get(ctx context.Context, request GetUserFieldRequest)
 		return nil, err
 	}
 
-	endpoint, err := c.rowndURL("applications", c.appID, "users", request.UserID, "data", "fields", request.Field)
+	endpoint, err := c.endpoint(c.endpoints.UserFields.Get, c.appID, request.UserID, request.Field)
 	if err != nil {
 		return nil, err
 	}
 
-	endpoint.RawQuery = request.params().Encode()
+	endpoint.URL.RawQuery = request.params().Encode()
 
 	var response map[string]any
-	if err := c.request(ctx, http.MethodGet, endpoint.String(), nil, &response, c.httpClientOpts...); err != nil {
+	if err := c.request(ctx, endpoint, nil, &response); err != nil {
 		return nil, err
 	}
 
@@ -101,23 +101,137 @@ func (r UpdateUserFieldRequest) validate() error {
 	return &MultiError{errors: errs}
 }
 
-// Update updates an existing user field.
+// Update updates an existing user field. The value is checked against the type of the field in
+// the app's schema before it is sent.
 func (c *userFieldClient) Update(ctx context.Context, request UpdateUserFieldRequest) error {
 	if err := request.validate(); err != nil {
 		return err
 	}
+	if err := c.checkFieldType(request.Field, request.Value); err != nil {
+		return err
+	}
 
-	endpoint, err := c.rowndURL("applications", c.appID, "users", request.UserID, "data", "fields", request.Field)
+	endpoint, err := c.endpoint(c.endpoints.UserFields.Update, c.appID, request.UserID, request.Field)
 	if err != nil {
 		return err
 	}
 
-	fmt.Printf("Making field update request to: %s\n", endpoint.String())
-
-	if err := c.request(ctx, http.MethodPut, endpoint.String(), request, nil, c.httpClientOpts...); err != nil {
-		fmt.Printf("Field update error: %v\n", err)
+	if err := c.request(ctx, endpoint, request, nil); err != nil {
 		return err
 	}
 
 	return nil
 }
+
+// DeleteUserFieldRequest ...
+type DeleteUserFieldRequest struct {
+	UserID string
+	Field  string
+}
+
+func (r DeleteUserFieldRequest) validate() error {
+	var errs []error
+
+	if r.UserID == "" {
+		errs = append(errs, NewError(ErrValidation, "user id is required", nil))
+	}
+	if r.Field == "" {
+		errs = append(errs, NewError(ErrValidation, "field is required", nil))
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+
+	return &MultiError{errors: errs}
+}
+
+// Delete removes a field from the user's profile.
+func (c *userFieldClient) Delete(ctx context.Context, request DeleteUserFieldRequest) error {
+	if err := request.validate(); err != nil {
+		return err
+	}
+
+	patch := PatchUserRequest{UserID: request.UserID}
+	patch.Unset(request.Field)
+
+	_, err := c.Users.Patch(ctx, patch)
+	return err
+}
+
+// GetUserFieldsRequest ...
+type GetUserFieldsRequest struct {
+	UserID string
+	Fields []string
+
+	// Concurrency is the number of fields fetched at once. Defaults to 4.
+	Concurrency int
+}
+
+func (r GetUserFieldsRequest) validate() error {
+	var errs []error
+
+	if r.UserID == "" {
+		errs = append(errs, NewError(ErrValidation, "user id is required", nil))
+	}
+	if len(r.Fields) == 0 {
+		errs = append(errs, NewError(ErrValidation, "at least one field is required", nil))
+	}
+	if r.Concurrency < 0 {
+		errs = append(errs, NewError(ErrValidation, "concurrency must not be negative", nil))
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+
+	return &MultiError{errors: errs}
+}
+
+// GetFields retrieves several fields of a user concurrently. Fields that could not be retrieved
+// are missing from the result and reported together in the returned error.
+func (c *userFieldClient) GetFields(ctx context.Context, request GetUserFieldsRequest) (map[string]any, error) {
+	if err := request.validate(); err != nil {
+		return nil, err
+	}
+
+	concurrency := request.Concurrency
+	if concurrency == 0 {
+		concurrency = defaultBulkConcurrency
+	}
+
+	var (
+		mu     sync.Mutex
+		wg     sync.WaitGroup
+		errs   []error
+		values = make(map[string]any, len(request.Fields))
+		sem    = make(chan struct{}, concurrency)
+	)
+	for _, field := range request.Fields {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(field string) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			value, err := c.Get(ctx, GetUserFieldRequest{UserID: request.UserID, Field: field})
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs = append(errs, fmt.Errorf("field %s: %w", field, err))
+				return
+			}
+			values[field] = value
+		}(field)
+	}
+	wg.Wait()
+
+	if len(errs) > 0 {
+		return values, &MultiError{errors: errs}
+	}
+
+	return values, nil
+}
@@ -0,0 +1,399 @@
+package rownd
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ImportFormat is the input format of a user import.
+type ImportFormat string
+
+const (
+	ImportFormatCSV       ImportFormat = "csv"
+	ImportFormatJSONLines ImportFormat = "jsonl"
+)
+
+func (f ImportFormat) validate() bool {
+	switch f {
+	case ImportFormatCSV, ImportFormatJSONLines:
+		return true
+	default:
+		return false
+	}
+}
+
+// ImportMode determines whether an import creates new users, updates existing ones, or both.
+type ImportMode string
+
+const (
+	ImportModeUpsert     ImportMode = "upsert"
+	ImportModeCreateOnly ImportMode = "create_only"
+	ImportModeUpdateOnly ImportMode = "update_only"
+)
+
+func (m ImportMode) validate() bool {
+	switch m {
+	case ImportModeUpsert, ImportModeCreateOnly, ImportModeUpdateOnly:
+		return true
+	default:
+		return false
+	}
+}
+
+// ImportAction is what an import did, or would do in a dry run, with a row.
+type ImportAction string
+
+const (
+	ImportActionCreate ImportAction = "create"
+	ImportActionUpdate ImportAction = "update"
+	ImportActionSkip   ImportAction = "skip"
+	ImportActionReject ImportAction = "reject"
+)
+
+// ImportOptions configures a user import.
+type ImportOptions struct {
+	// Format is the input format. Defaults to CSV.
+	Format ImportFormat
+
+	// Mapping maps source columns (CSV) or keys (JSON Lines) to profile fields. Source columns
+	// without a mapping are ignored. When nil, every column is imported under its own name.
+	Mapping map[string]string
+
+	// LookupField is the profile field, after mapping, used to find existing users, e.g. "email".
+	// It is required unless Mode is ImportModeCreateOnly.
+	LookupField string
+
+	// Mode defaults to ImportModeUpsert.
+	Mode ImportMode
+
+	// DryRun resolves what would happen to every row without writing any changes.
+	DryRun bool
+
+	// Errors receives rejected rows in the input format with the reason appended, as an "error"
+	// column for CSV or an "error" key for JSON Lines.
+	Errors io.Writer
+
+	// WriteDataToIntegrations is passed through to the create and update requests.
+	WriteDataToIntegrations *bool
+}
+
+func (o ImportOptions) validate() error {
+	var errs []error
+
+	if o.Format != "" && !o.Format.validate() {
+		errs = append(errs, NewError(ErrValidation, "invalid import format", nil))
+	}
+	if o.Mode != "" && !o.Mode.validate() {
+		errs = append(errs, NewError(ErrValidation, "invalid import mode", nil))
+	}
+	if o.LookupField == "" && o.Mode != ImportModeCreateOnly {
+		errs = append(errs, NewError(ErrValidation, "lookup field is required unless mode is create_only", nil))
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+
+	return &MultiError{errors: errs}
+}
+
+// ImportRowResult is the outcome of a single row.
+type ImportRowResult struct {
+	// Row is the 1-based data row number, excluding the CSV header.
+	Row    int
+	Action ImportAction
+	UserID string
+	Err    error
+}
+
+// ImportReport summarises an import.
+type ImportReport struct {
+	DryRun   bool
+	Rows     int
+	Created  int
+	Updated  int
+	Skipped  int
+	Rejected int
+	Results  []ImportRowResult
+}
+
+func (r *ImportReport) add(result ImportRowResult) {
+	r.Rows++
+	r.Results = append(r.Results, result)
+
+	switch result.Action {
+	case ImportActionCreate:
+		r.Created++
+	case ImportActionUpdate:
+		r.Updated++
+	case ImportActionSkip:
+		r.Skipped++
+	case ImportActionReject:
+		r.Rejected++
+	}
+}
+
+// importReader yields rows from the input.
+type importReader interface {
+	// next returns the next record, or io.EOF once the input is exhausted. Records that cannot be
+	// parsed are returned with an ErrValidation error so they can be rejected individually.
+	next() (map[string]any, error)
+	// reject writes a rejected record to the errors output.
+	reject(record map[string]any, reason error) error
+}
+
+// Import reads users from r and creates or updates them according to the options. Rows are
+// processed one at a time, so the input is never held in memory. Row-level failures are recorded
+// in the report; only invalid options, unreadable input or a cancelled context return an error.
+func (c *userClient) Import(ctx context.Context, r io.Reader, opts ImportOptions) (*ImportReport, error) {
+	if err := opts.validate(); err != nil {
+		return nil, err
+	}
+	if opts.Mode == "" {
+		opts.Mode = ImportModeUpsert
+	}
+
+	var reader importReader
+	switch opts.Format {
+	case ImportFormatJSONLines:
+		reader = newJSONLinesImportReader(r, opts.Errors)
+	default:
+		var err error
+		if reader, err = newCSVImportReader(r, opts.Errors); err != nil {
+			return nil, err
+		}
+	}
+
+	report := &ImportReport{DryRun: opts.DryRun}
+	for row := 1; ; row++ {
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+
+		var result ImportRowResult
+		record, err := reader.next()
+		switch {
+		case errors.Is(err, io.EOF):
+			return report, nil
+		case KindOf(err) == ErrValidation:
+			result = ImportRowResult{Action: ImportActionReject, Err: err}
+		case err != nil:
+			return report, fmt.Errorf("failed to read row %d: %w", row, err)
+		default:
+			result = c.importRow(ctx, record, opts)
+		}
+		result.Row = row
+		report.add(result)
+
+		if result.Action == ImportActionReject {
+			if err := reader.reject(record, result.Err); err != nil {
+				return report, fmt.Errorf("failed to write rejected row %d: %w", row, err)
+			}
+		}
+	}
+}
+
+func (c *userClient) importRow(ctx context.Context, record map[string]any, opts ImportOptions) ImportRowResult {
+	reject := func(err error) ImportRowResult {
+		return ImportRowResult{Action: ImportActionReject, Err: err}
+	}
+
+	data := map[string]any{}
+	for column, value := range record {
+		field := column
+		if opts.Mapping != nil {
+			var ok bool
+			if field, ok = opts.Mapping[column]; !ok {
+				continue
+			}
+		}
+		if s, ok := value.(string); ok && s == "" {
+			continue
+		}
+		data[field] = value
+	}
+	if len(data) == 0 {
+		return reject(NewError(ErrValidation, "row has no mapped fields", nil))
+	}
+
+	var existing *User
+	if opts.LookupField != "" {
+		lookup, ok := data[opts.LookupField]
+		if !ok {
+			return reject(NewError(ErrValidation, fmt.Sprintf("lookup field %s is missing", opts.LookupField), nil))
+		}
+
+		matches, err := c.List(ctx, ListUsersRequest{
+			LookupFilter:      []string{fmt.Sprint(lookup)},
+			IncludeDuplicates: ToPointer(true),
+		})
+		if err != nil {
+			return reject(err)
+		}
+		switch len(matches.Results) {
+		case 0:
+		case 1:
+			existing = &matches.Results[0]
+		default:
+			return reject(NewError(ErrValidation, fmt.Sprintf("lookup value %v matches %d users", lookup, len(matches.Results)), nil))
+		}
+	}
+
+	switch {
+	case existing != nil && opts.Mode == ImportModeCreateOnly:
+		return ImportRowResult{Action: ImportActionSkip, UserID: existing.GetID()}
+	case existing == nil && opts.Mode == ImportModeUpdateOnly:
+		return ImportRowResult{Action: ImportActionSkip}
+	case existing != nil:
+		if !opts.DryRun {
+			if _, err := c.Patch(ctx, PatchUserRequest{
+				UserID:                  existing.GetID(),
+				WriteDataToIntegrations: opts.WriteDataToIntegrations,
+				Data:                    data,
+			}); err != nil {
+				return reject(err)
+			}
+		}
+		return ImportRowResult{Action: ImportActionUpdate, UserID: existing.GetID()}
+	default:
+		if opts.DryRun {
+			return ImportRowResult{Action: ImportActionCreate}
+		}
+		user, err := c.CreateOrUpdate(ctx, CreateOrUpdateUserRequest{
+			UserID:                  UserIDDefault,
+			WriteDataToIntegrations: opts.WriteDataToIntegrations,
+			Data:                    data,
+		})
+		if err != nil {
+			return reject(err)
+		}
+		return ImportRowResult{Action: ImportActionCreate, UserID: user.GetID()}
+	}
+}
+
+type csvImportReader struct {
+	r        *csv.Reader
+	header   []string
+	rejected io.Writer
+	errors   *csv.Writer
+}
+
+func newCSVImportReader(r io.Reader, rejected io.Writer) (*csvImportReader, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+
+	header, err := cr.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read csv header: %w", err)
+	}
+	for i := range header {
+		header[i] = strings.TrimSpace(header[i])
+	}
+
+	return &csvImportReader{r: cr, header: header, rejected: rejected}, nil
+}
+
+func (r *csvImportReader) next() (map[string]any, error) {
+	row, err := r.r.Read()
+	var parseErr *csv.ParseError
+	if err != nil && !errors.As(err, &parseErr) {
+		return nil, err
+	}
+
+	record := make(map[string]any, len(r.header))
+	for i, column := range r.header {
+		if i < len(row) {
+			record[column] = row[i]
+		}
+	}
+	if parseErr != nil {
+		return record, NewError(ErrValidation, "malformed csv", parseErr)
+	}
+
+	return record, nil
+}
+
+func (r *csvImportReader) reject(record map[string]any, reason error) error {
+	if r.rejected == nil {
+		return nil
+	}
+	if r.errors == nil {
+		r.errors = csv.NewWriter(r.rejected)
+		if err := r.errors.Write(append(append([]string{}, r.header...), "error")); err != nil {
+			return err
+		}
+	}
+
+	row := make([]string, 0, len(r.header)+1)
+	for _, column := range r.header {
+		value, ok := record[column]
+		if !ok {
+			row = append(row, "")
+			continue
+		}
+		row = append(row, fmt.Sprint(value))
+	}
+	if err := r.errors.Write(append(row, reason.Error())); err != nil {
+		return err
+	}
+
+	r.errors.Flush()
+	return r.errors.Error()
+}
+
+type jsonLinesImportReader struct {
+	scanner *bufio.Scanner
+	errors  *json.Encoder
+}
+
+func newJSONLinesImportReader(r io.Reader, rejected io.Writer) *jsonLinesImportReader {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 10*1024*1024)
+
+	reader := &jsonLinesImportReader{scanner: scanner}
+	if rejected != nil {
+		reader.errors = json.NewEncoder(rejected)
+	}
+
+	return reader
+}
+
+func (r *jsonLinesImportReader) next() (map[string]any, error) {
+	for r.scanner.Scan() {
+		line := strings.TrimSpace(r.scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		var record map[string]any
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			return map[string]any{"raw": line}, NewError(ErrValidation, "malformed json", err)
+		}
+		return record, nil
+	}
+	if err := r.scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return nil, io.EOF
+}
+
+func (r *jsonLinesImportReader) reject(record map[string]any, reason error) error {
+	if r.errors == nil {
+		return nil
+	}
+
+	rejected := make(map[string]any, len(record)+1)
+	for k, v := range record {
+		rejected[k] = v
+	}
+	rejected["error"] = reason.Error()
+
+	return r.errors.Encode(rejected)
+}
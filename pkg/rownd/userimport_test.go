@@ -0,0 +1,175 @@
+package rownd_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/rownd/client-go/pkg/rownd"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUserImport(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		patched = map[string]map[string]any{}
+		created []map[string]any
+	)
+
+	existing := map[string]string{"alice@example.com": "user_alice"}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/applications/", func(w http.ResponseWriter, r *http.Request) {
+		segments := pathSegments(r)
+		mu.Lock()
+		defer mu.Unlock()
+
+		switch {
+		case r.Method == http.MethodGet && len(segments) == 4:
+			var results []map[string]any
+			if id, ok := existing[r.URL.Query().Get("lookup_filter")]; ok {
+				results = append(results, map[string]any{"rownd_user": id})
+			}
+			// the API returns only the first match unless duplicates are asked for
+			if r.URL.Query().Get("lookup_filter") == "shared@example.com" {
+				results = append(results, map[string]any{"rownd_user": "user_shared_1"})
+				if r.URL.Query().Get("include_duplicates") == "true" {
+					results = append(results, map[string]any{"rownd_user": "user_shared_2"})
+				}
+			}
+			writeJSON(w, http.StatusOK, map[string]any{"total_results": len(results), "results": results})
+		case r.Method == http.MethodPatch:
+			var body struct {
+				Data map[string]any `json:"data"`
+			}
+			json.NewDecoder(r.Body).Decode(&body)
+			patched[segments[3]] = body.Data
+			writeJSON(w, http.StatusOK, map[string]any{"data": body.Data})
+		case r.Method == http.MethodPut:
+			assert.Equal(t, rownd.UserIDDefault, segments[3])
+			var body struct {
+				Data map[string]any `json:"data"`
+			}
+			json.NewDecoder(r.Body).Decode(&body)
+			created = append(created, body.Data)
+			body.Data["user_id"] = "user_new"
+			writeJSON(w, http.StatusOK, map[string]any{"data": body.Data})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	client := newTestClient(t, mux)
+	ctx := context.Background()
+
+	input := "Email,First Name,Ignored\n" +
+		"alice@example.com,Alice,x\n" +
+		"bob@example.com,Bob,y\n" +
+		",Nobody,z\n"
+	mapping := map[string]string{"Email": "email", "First Name": "first_name"}
+
+	t.Run("dry run", func(t *testing.T) {
+		report, err := client.Users.Import(ctx, strings.NewReader(input), rownd.ImportOptions{
+			Mapping:     mapping,
+			LookupField: "email",
+			DryRun:      true,
+		})
+		assert.NoError(t, err)
+		assert.True(t, report.DryRun)
+		assert.Equal(t, 3, report.Rows)
+		assert.Equal(t, 1, report.Updated)
+		assert.Equal(t, 1, report.Created)
+		assert.Equal(t, 1, report.Rejected)
+		assert.Empty(t, patched)
+		assert.Empty(t, created)
+	})
+
+	t.Run("upsert", func(t *testing.T) {
+		var rejected bytes.Buffer
+		report, err := client.Users.Import(ctx, strings.NewReader(input), rownd.ImportOptions{
+			Mapping:     mapping,
+			LookupField: "email",
+			Errors:      &rejected,
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 1, report.Updated)
+		assert.Equal(t, 1, report.Created)
+		assert.Equal(t, "user_new", report.Results[1].UserID)
+
+		assert.Equal(t, map[string]any{"email": "alice@example.com", "first_name": "Alice"}, patched["user_alice"])
+		assert.Equal(t, "bob@example.com", created[0]["email"])
+
+		rows, err := csv.NewReader(&rejected).ReadAll()
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"Email", "First Name", "Ignored", "error"}, rows[0])
+		assert.Equal(t, "Nobody", rows[1][1])
+		assert.Contains(t, rows[1][3], "lookup field email is missing")
+	})
+
+	t.Run("malformed csv rows", func(t *testing.T) {
+		var rejected bytes.Buffer
+		report, err := client.Users.Import(ctx, strings.NewReader(
+			"Email,First Name,Ignored\n"+
+				"carol\"@example.com,Carol,w\n"+
+				",Dan\n"+
+				"bob@example.com,Bob,y\n",
+		), rownd.ImportOptions{
+			Mapping:     mapping,
+			LookupField: "email",
+			DryRun:      true,
+			Errors:      &rejected,
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 3, report.Rows)
+		assert.Equal(t, 2, report.Rejected)
+		assert.Equal(t, 1, report.Created)
+		assert.Equal(t, rownd.ErrValidation, rownd.KindOf(report.Results[0].Err))
+
+		rows, err := csv.NewReader(&rejected).ReadAll()
+		assert.NoError(t, err)
+		assert.Len(t, rows, 3)
+		assert.Contains(t, rows[1][3], "malformed csv")
+		assert.Equal(t, []string{"", "Dan", ""}, rows[2][:3])
+	})
+
+	t.Run("ambiguous lookup", func(t *testing.T) {
+		report, err := client.Users.Import(ctx, strings.NewReader("Email,First Name\nshared@example.com,Sam\n"), rownd.ImportOptions{
+			Mapping:     mapping,
+			LookupField: "email",
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 1, report.Rejected)
+		assert.Contains(t, report.Results[0].Err.Error(), "matches 2 users")
+		assert.NotContains(t, patched, "user_shared_1")
+	})
+
+	t.Run("update only json lines", func(t *testing.T) {
+		var rejected bytes.Buffer
+		report, err := client.Users.Import(ctx, strings.NewReader(
+			`{"email":"alice@example.com","plan":"pro"}`+"\n"+
+				`{"email":"carol@example.com","plan":"pro"}`+"\n"+
+				`not json`+"\n",
+		), rownd.ImportOptions{
+			Format:      rownd.ImportFormatJSONLines,
+			LookupField: "email",
+			Mode:        rownd.ImportModeUpdateOnly,
+			Errors:      &rejected,
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 1, report.Updated)
+		assert.Equal(t, 1, report.Skipped)
+		assert.Equal(t, 1, report.Rejected)
+		assert.Equal(t, "pro", patched["user_alice"]["plan"])
+		assert.Contains(t, rejected.String(), "malformed json")
+	})
+
+	t.Run("lookup field required", func(t *testing.T) {
+		_, err := client.Users.Import(ctx, strings.NewReader(input), rownd.ImportOptions{})
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,174 @@
+package rownd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// UserQueryBuilder builds user list requests. Use UserQuery to create one.
+type UserQueryBuilder struct {
+	lookups           []string
+	ids               []string
+	fields            []string
+	pageSize          *int
+	sort              *Sort
+	after             *string
+	includeDuplicates *bool
+}
+
+// UserQuery starts a new user query.
+//
+//	query := rownd.UserQuery().
+//		WhereLookup("user@example.com").
+//		Select("email", "first_name").
+//		SortDesc().
+//		PageSize(500)
+//	users, err := client.Users.Query(ctx, query)
+func UserQuery() *UserQueryBuilder {
+	return &UserQueryBuilder{}
+}
+
+// WhereLookup matches users by lookup values such as email addresses or phone numbers.
+func (q *UserQueryBuilder) WhereLookup(values ...string) *UserQueryBuilder {
+	q.lookups = appendUnique(q.lookups, values...)
+	return q
+}
+
+// WithIDs restricts the results to users with these IDs.
+func (q *UserQueryBuilder) WithIDs(ids ...string) *UserQueryBuilder {
+	q.ids = appendUnique(q.ids, ids...)
+	return q
+}
+
+// Select limits the profile data returned to these fields.
+func (q *UserQueryBuilder) Select(fields ...string) *UserQueryBuilder {
+	q.fields = appendUnique(q.fields, fields...)
+	return q
+}
+
+// SortAsc sorts the results in ascending order.
+func (q *UserQueryBuilder) SortAsc() *UserQueryBuilder {
+	q.sort = ToPointer(SortAsc)
+	return q
+}
+
+// SortDesc sorts the results in descending order.
+func (q *UserQueryBuilder) SortDesc() *UserQueryBuilder {
+	q.sort = ToPointer(SortDesc)
+	return q
+}
+
+// PageSize sets the number of users returned per request, at most 1000.
+func (q *UserQueryBuilder) PageSize(n int) *UserQueryBuilder {
+	q.pageSize = ToPointer(n)
+	return q
+}
+
+// After starts the results after the user with this ID.
+func (q *UserQueryBuilder) After(userID string) *UserQueryBuilder {
+	q.after = ToPointer(userID)
+	return q
+}
+
+// IncludeDuplicates returns every user matching a filter value rather than only the first.
+func (q *UserQueryBuilder) IncludeDuplicates(include bool) *UserQueryBuilder {
+	q.includeDuplicates = ToPointer(include)
+	return q
+}
+
+// Build returns the list requests for the query. An ID filter longer than fits in one request is
+// split across several requests, each with a page size large enough to return all of its users.
+func (q *UserQueryBuilder) Build() ([]ListUsersRequest, error) {
+	base := ListUsersRequest{
+		Fields:            q.fields,
+		LookupFilter:      q.lookups,
+		PageSize:          q.pageSize,
+		After:             q.after,
+		Sort:              q.sort,
+		IncludeDuplicates: q.includeDuplicates,
+	}
+	if err := base.validate(); err != nil {
+		return nil, err
+	}
+
+	chunkSize := maxIDFilterSize
+	if q.pageSize != nil {
+		chunkSize = min(chunkSize, *q.pageSize)
+	}
+	if len(q.ids) <= chunkSize {
+		base.IDFilter = q.ids
+		return []ListUsersRequest{base}, nil
+	}
+
+	if q.after != nil {
+		return nil, NewError(ErrValidation, fmt.Sprintf("after cannot be combined with more than %d ids", chunkSize), nil)
+	}
+
+	var requests []ListUsersRequest
+	for start := 0; start < len(q.ids); start += chunkSize {
+		request := base
+		request.IDFilter = q.ids[start:min(start+chunkSize, len(q.ids))]
+		if request.PageSize == nil {
+			request.PageSize = ToPointer(chunkSize)
+		}
+		requests = append(requests, request)
+	}
+
+	return requests, nil
+}
+
+// Query runs a user query. When the query is split into several requests, their results are
+// merged in request order with users returned more than once dropped, and TotalResults is the
+// number of merged users.
+func (c *userClient) Query(ctx context.Context, query *UserQueryBuilder) (*ListUsersResponse, error) {
+	requests, err := query.Build()
+	if err != nil {
+		return nil, err
+	}
+	if len(requests) == 1 {
+		return c.List(ctx, requests[0])
+	}
+
+	merged := &ListUsersResponse{Results: []User{}}
+	seen := map[string]bool{}
+	for _, request := range requests {
+		response, err := c.List(ctx, request)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, u := range response.Results {
+			id := u.GetID()
+			if id != "" && seen[id] {
+				continue
+			}
+			seen[id] = true
+			merged.Results = append(merged.Results, u)
+		}
+	}
+	merged.TotalResults = len(merged.Results)
+
+	return merged, nil
+}
+
+// appendUnique appends the trimmed, non-empty values not already present in s.
+func appendUnique(s []string, values ...string) []string {
+	for _, v := range values {
+		v = strings.TrimSpace(v)
+		if v == "" || containsString(s, v) {
+			continue
+		}
+		s = append(s, v)
+	}
+	return s
+}
+
+func containsString(s []string, v string) bool {
+	for _, existing := range s {
+		if existing == v {
+			return true
+		}
+	}
+	return false
+}
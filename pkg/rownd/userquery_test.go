@@ -0,0 +1,91 @@
+package rownd_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/rownd/client-go/pkg/rownd"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUserQueryBuild(t *testing.T) {
+	requests, err := rownd.UserQuery().
+		WhereLookup("a@example.com", " a@example.com", "").
+		WithIDs("user_1", "user_1", "user_2").
+		Select("email", "name", "email").
+		SortDesc().
+		PageSize(500).
+		Build()
+	assert.NoError(t, err)
+	if assert.Len(t, requests, 1) {
+		assert.Equal(t, []string{"a@example.com"}, requests[0].LookupFilter)
+		assert.Equal(t, []string{"user_1", "user_2"}, requests[0].IDFilter)
+		assert.Equal(t, []string{"email", "name"}, requests[0].Fields)
+		assert.Equal(t, rownd.SortDesc, *requests[0].Sort)
+		assert.Equal(t, 500, *requests[0].PageSize)
+	}
+
+	_, err = rownd.UserQuery().PageSize(1001).Build()
+	assert.Equal(t, rownd.ErrValidation, rownd.KindOf(err))
+
+	var ids []string
+	for i := 0; i < 250; i++ {
+		ids = append(ids, fmt.Sprintf("user_%d", i))
+	}
+
+	requests, err = rownd.UserQuery().WithIDs(ids...).Build()
+	assert.NoError(t, err)
+	if assert.Len(t, requests, 3) {
+		assert.Len(t, requests[0].IDFilter, 100)
+		assert.Len(t, requests[2].IDFilter, 50)
+		assert.Equal(t, 100, *requests[2].PageSize)
+	}
+
+	requests, err = rownd.UserQuery().WithIDs(ids...).PageSize(50).Build()
+	assert.NoError(t, err)
+	assert.Len(t, requests, 5)
+
+	_, err = rownd.UserQuery().WithIDs(ids...).After("user_0").Build()
+	assert.Error(t, err)
+}
+
+func TestUserQuery(t *testing.T) {
+	var (
+		mu         sync.Mutex
+		queries    []string
+		duplicates []string
+	)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/applications/", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		queries = append(queries, r.URL.Query().Get("id_filter"))
+		duplicates = append(duplicates, r.URL.Query().Get("include_duplicates"))
+		mu.Unlock()
+
+		var results []map[string]any
+		for _, id := range strings.Split(r.URL.Query().Get("id_filter"), ",") {
+			results = append(results, map[string]any{"rownd_user": id})
+		}
+		// a duplicate that also shows up in the next chunk
+		results = append(results, map[string]any{"rownd_user": "user_100"})
+		writeJSON(w, http.StatusOK, map[string]any{"total_results": len(results), "results": results})
+	})
+	client := newTestClient(t, mux)
+
+	var ids []string
+	for i := 0; i < 150; i++ {
+		ids = append(ids, fmt.Sprintf("user_%d", i))
+	}
+
+	response, err := client.Users.Query(context.Background(), rownd.UserQuery().WithIDs(ids...).IncludeDuplicates(true))
+	assert.NoError(t, err)
+	assert.Len(t, queries, 2)
+	assert.Equal(t, []string{"true", "true"}, duplicates, "include_duplicates is sent with every request")
+	assert.Equal(t, 150, response.TotalResults)
+	assert.Len(t, response.Results, 150)
+}
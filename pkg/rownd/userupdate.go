@@ -0,0 +1,155 @@
+package rownd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"time"
+)
+
+const (
+	defaultUpdateMaxAttempts int           = 5
+	defaultUpdateRetryDelay  time.Duration = 100 * time.Millisecond
+)
+
+// UpdateOption ...
+type UpdateOption interface {
+	apply(*updateOptions)
+}
+
+type updateOptions struct {
+	maxAttempts int
+	retryDelay  time.Duration
+}
+
+type updateMaxAttemptsOpt int
+
+func (o updateMaxAttemptsOpt) apply(opts *updateOptions) {
+	opts.maxAttempts = int(o)
+}
+
+// WithUpdateMaxAttempts sets how many times Update runs the mutation before giving up with a
+// ConflictError. Defaults to 5.
+func WithUpdateMaxAttempts(n int) UpdateOption {
+	return updateMaxAttemptsOpt(n)
+}
+
+type updateRetryDelayOpt time.Duration
+
+func (o updateRetryDelayOpt) apply(opts *updateOptions) {
+	opts.retryDelay = time.Duration(o)
+}
+
+// WithUpdateRetryDelay sets the delay, multiplied by the attempt number, before Update retries
+// after a conflict. Defaults to 100ms.
+func WithUpdateRetryDelay(d time.Duration) UpdateOption {
+	return updateRetryDelayOpt(d)
+}
+
+// Update performs a read-modify-write of a user profile. The user is fetched, passed to mutate,
+// and the changes to Data are sent back as a minimal merge patch (see MergePatch). If the profile
+// was modified by someone else in the meantime, detected through Meta.Modified and the profile
+// data or a 409/412 response from the API, mutate is run again on a fresh copy. The API has no
+// conditional writes, so a modification landing between the final check and the patch can still
+// be overwritten; Update only narrows that window to one request.
+//
+// mutate may be called several times and should have no side effects. An error returned by
+// mutate aborts the update and is returned as is. When every attempt conflicts, Update returns a
+// *ConflictError.
+func (c *userClient) Update(ctx context.Context, userID string, mutate func(*User) error, opts ...UpdateOption) (*User, error) {
+	o := updateOptions{
+		maxAttempts: defaultUpdateMaxAttempts,
+		retryDelay:  defaultUpdateRetryDelay,
+	}
+	for _, opt := range opts {
+		opt.apply(&o)
+	}
+
+	var errs []error
+	if userID == "" {
+		errs = append(errs, NewError(ErrValidation, "user id is required", nil))
+	}
+	if mutate == nil {
+		errs = append(errs, NewError(ErrValidation, "mutate function is required", nil))
+	}
+	if o.maxAttempts < 1 {
+		errs = append(errs, NewError(ErrValidation, "max attempts must be at least 1", nil))
+	}
+	if len(errs) > 0 {
+		return nil, &MultiError{errors: errs}
+	}
+
+	var lastConflict error
+	for attempt := 1; attempt <= o.maxAttempts; attempt++ {
+		if attempt > 1 {
+			timer := time.NewTimer(time.Duration(attempt-1) * o.retryDelay)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return nil, ctx.Err()
+			case <-timer.C:
+			}
+		}
+
+		user, err := c.updateOnce(ctx, userID, mutate)
+		if err == nil {
+			return user, nil
+		}
+		if KindOf(err) != ErrConflict {
+			return nil, err
+		}
+		lastConflict = err
+	}
+
+	conflict := &ConflictError{UserID: userID, Attempts: o.maxAttempts}
+	if _, ok := lastConflict.(*ConflictError); !ok {
+		conflict.Err = lastConflict
+	}
+	return nil, conflict
+}
+
+func (c *userClient) updateOnce(ctx context.Context, userID string, mutate func(*User) error) (*User, error) {
+	current, err := c.Get(ctx, GetUserRequest{UserID: userID})
+	if err != nil {
+		return nil, err
+	}
+
+	working, err := cloneUser(current)
+	if err != nil {
+		return nil, err
+	}
+	if err := mutate(working); err != nil {
+		return nil, err
+	}
+
+	changes := MergePatch(current.Data, working.Data)
+	if len(changes) == 0 {
+		return current, nil
+	}
+
+	latest, err := c.Get(ctx, GetUserRequest{UserID: userID})
+	if err != nil {
+		return nil, err
+	}
+	if !latest.Meta.Modified.Equal(current.Meta.Modified) || !reflect.DeepEqual(latest.Data, current.Data) {
+		return nil, &ConflictError{UserID: userID, Attempts: 1}
+	}
+
+	return c.Patch(ctx, PatchUserRequest{UserID: userID, Data: changes})
+}
+
+// cloneUser deep copies a user so mutations of nested data don't leak into the original.
+func cloneUser(u *User) (*User, error) {
+	b, err := json.Marshal(u)
+	if err != nil {
+		return nil, fmt.Errorf("failed to copy user: %w", err)
+	}
+
+	var clone *User
+	if err := json.Unmarshal(b, &clone); err != nil {
+		return nil, fmt.Errorf("failed to copy user: %w", err)
+	}
+
+	return clone, nil
+}
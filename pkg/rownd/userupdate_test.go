@@ -0,0 +1,161 @@
+package rownd_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rownd/client-go/pkg/rownd"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeProfile serves a single user profile whose modification time advances on every write.
+type fakeProfile struct {
+	mu       sync.Mutex
+	data     map[string]any
+	modified time.Time
+	patches  []map[string]any
+	// beforeGet is called for every read with the number of reads so far.
+	beforeGet func(p *fakeProfile, reads int)
+	reads     int
+}
+
+func (p *fakeProfile) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	switch r.Method {
+	case http.MethodGet:
+		p.reads++
+		if p.beforeGet != nil {
+			p.beforeGet(p, p.reads)
+		}
+	case http.MethodPatch:
+		var body struct {
+			Data map[string]any `json:"data"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		p.patches = append(p.patches, body.Data)
+		p.write(body.Data)
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"data": p.data,
+		"meta": map[string]any{"modified": p.modified},
+	})
+}
+
+// write merges the top level of data into the profile; nested objects replace the stored value
+// whole, as the API does.
+func (p *fakeProfile) write(data map[string]any) {
+	for k, v := range data {
+		if v == nil {
+			delete(p.data, k)
+		} else {
+			p.data[k] = v
+		}
+	}
+	p.modified = p.modified.Add(time.Second)
+}
+
+func TestUserUpdate(t *testing.T) {
+	ctx := context.Background()
+
+	newClient := func(t *testing.T, profile *fakeProfile) *rownd.Client {
+		mux := http.NewServeMux()
+		mux.Handle("/applications/", profile)
+		return newTestClient(t, mux)
+	}
+
+	t.Run("patches changed fields", func(t *testing.T) {
+		profile := &fakeProfile{data: map[string]any{"first_name": "Jane", "visits": 1.0, "legacy": "x"}}
+		client := newClient(t, profile)
+
+		user, err := client.Users.Update(ctx, "user_1", func(u *rownd.User) error {
+			u.Data["visits"] = u.Data["visits"].(float64) + 1
+			delete(u.Data, "legacy")
+			return nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 2.0, user.Data["visits"])
+		assert.Equal(t, []map[string]any{{"visits": 2.0, "legacy": nil}}, profile.patches)
+	})
+
+	t.Run("keeps sibling fields of nested objects", func(t *testing.T) {
+		profile := &fakeProfile{data: map[string]any{
+			"address": map[string]any{"city": "Denver", "zip": "80202"},
+		}}
+		client := newClient(t, profile)
+
+		_, err := client.Users.Update(ctx, "user_1", func(u *rownd.User) error {
+			u.Data["address"].(map[string]any)["city"] = "Boulder"
+			return nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]any{"city": "Boulder", "zip": "80202"}, profile.data["address"])
+	})
+
+	t.Run("no changes", func(t *testing.T) {
+		profile := &fakeProfile{data: map[string]any{"first_name": "Jane"}}
+		client := newClient(t, profile)
+
+		_, err := client.Users.Update(ctx, "user_1", func(u *rownd.User) error { return nil })
+		assert.NoError(t, err)
+		assert.Empty(t, profile.patches)
+	})
+
+	t.Run("retries on conflict", func(t *testing.T) {
+		profile := &fakeProfile{data: map[string]any{"visits": 1.0}}
+		profile.beforeGet = func(p *fakeProfile, reads int) {
+			// another writer sneaks in between the first read and the check
+			if reads == 2 {
+				p.write(map[string]any{"visits": 10.0})
+			}
+		}
+		client := newClient(t, profile)
+
+		calls := 0
+		user, err := client.Users.Update(ctx, "user_1", func(u *rownd.User) error {
+			calls++
+			u.Data["visits"] = u.Data["visits"].(float64) + 1
+			return nil
+		}, rownd.WithUpdateRetryDelay(time.Millisecond))
+		assert.NoError(t, err)
+		assert.Equal(t, 2, calls)
+		assert.Equal(t, 11.0, user.Data["visits"])
+	})
+
+	t.Run("gives up", func(t *testing.T) {
+		profile := &fakeProfile{data: map[string]any{"visits": 1.0}}
+		profile.beforeGet = func(p *fakeProfile, reads int) {
+			if reads%2 == 0 {
+				p.write(map[string]any{"other": float64(reads)})
+			}
+		}
+		client := newClient(t, profile)
+
+		_, err := client.Users.Update(ctx, "user_1", func(u *rownd.User) error {
+			u.Data["visits"] = 2.0
+			return nil
+		}, rownd.WithUpdateMaxAttempts(3), rownd.WithUpdateRetryDelay(time.Millisecond))
+
+		var conflict *rownd.ConflictError
+		assert.True(t, errors.As(err, &conflict))
+		assert.Equal(t, 3, conflict.Attempts)
+		assert.Equal(t, rownd.ErrConflict, rownd.KindOf(err))
+		assert.Empty(t, profile.patches)
+	})
+
+	t.Run("mutate error aborts", func(t *testing.T) {
+		profile := &fakeProfile{data: map[string]any{}}
+		client := newClient(t, profile)
+
+		boom := errors.New("boom")
+		_, err := client.Users.Update(ctx, "user_1", func(u *rownd.User) error { return boom })
+		assert.ErrorIs(t, err, boom)
+	})
+}
@@ -0,0 +1,344 @@
+package rownd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+)
+
+const defaultWatchInterval time.Duration = time.Minute
+
+// UserEventType ...
+type UserEventType string
+
+const (
+	UserCreated  UserEventType = "created"
+	UserUpdated  UserEventType = "updated"
+	UserDeleted  UserEventType = "deleted"
+	UserSignedIn UserEventType = "signed_in"
+)
+
+// UserEvent is a change to a user observed by Watch.
+type UserEvent struct {
+	Type   UserEventType
+	UserID string
+	// User is the current profile. It is nil for deleted users.
+	User *User
+	// Changes lists the profile data fields that changed. It is only set for updates.
+	Changes []FieldChange
+	// Time is when the change was observed.
+	Time time.Time
+}
+
+// WatchCheckpoint is the state Watch compares each scan against.
+type WatchCheckpoint struct {
+	Version   int                    `json:"version"`
+	UpdatedAt time.Time              `json:"updated_at"`
+	Users     map[string]WatchedUser `json:"users"`
+}
+
+// WatchedUser is the last observed state of a user.
+type WatchedUser struct {
+	Modified   time.Time      `json:"modified"`
+	LastSignIn time.Time      `json:"last_sign_in"`
+	Data       map[string]any `json:"data"`
+}
+
+// CheckpointStore persists the watch checkpoint between scans and restarts.
+type CheckpointStore interface {
+	// Load returns the saved checkpoint, or nil if there is none.
+	Load(ctx context.Context) (*WatchCheckpoint, error)
+	Save(ctx context.Context, checkpoint *WatchCheckpoint) error
+}
+
+// MemoryCheckpointStore keeps the checkpoint in memory.
+type MemoryCheckpointStore struct {
+	mu         sync.Mutex
+	checkpoint *WatchCheckpoint
+}
+
+// NewMemoryCheckpointStore ...
+func NewMemoryCheckpointStore() *MemoryCheckpointStore {
+	return &MemoryCheckpointStore{}
+}
+
+// Load implements CheckpointStore.
+func (s *MemoryCheckpointStore) Load(_ context.Context) (*WatchCheckpoint, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.checkpoint, nil
+}
+
+// Save implements CheckpointStore.
+func (s *MemoryCheckpointStore) Save(_ context.Context, checkpoint *WatchCheckpoint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.checkpoint = checkpoint
+	return nil
+}
+
+// FileCheckpointStore keeps the checkpoint in a JSON file. Saves are atomic: the checkpoint is
+// written to a temporary file that replaces the previous one.
+type FileCheckpointStore struct {
+	path string
+}
+
+// NewFileCheckpointStore ...
+func NewFileCheckpointStore(path string) *FileCheckpointStore {
+	return &FileCheckpointStore{path: path}
+}
+
+// Load implements CheckpointStore.
+func (s *FileCheckpointStore) Load(_ context.Context) (*WatchCheckpoint, error) {
+	b, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read checkpoint: %w", err)
+	}
+
+	var checkpoint *WatchCheckpoint
+	if err := json.Unmarshal(b, &checkpoint); err != nil {
+		return nil, fmt.Errorf("failed to parse checkpoint: %w", err)
+	}
+
+	return checkpoint, nil
+}
+
+// Save implements CheckpointStore.
+func (s *FileCheckpointStore) Save(_ context.Context, checkpoint *WatchCheckpoint) error {
+	b, err := json.Marshal(checkpoint)
+	if err != nil {
+		return fmt.Errorf("failed to encode checkpoint: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+
+	return nil
+}
+
+// WatchOptions configures Watch. Exactly one of Handler and Events must be set.
+type WatchOptions struct {
+	// Interval is the time between scans. Defaults to one minute.
+	Interval time.Duration
+
+	// Request holds filters applied while paging through users. Users that stop matching the
+	// filters are reported as deleted.
+	Request ListUsersRequest
+
+	// Store persists the checkpoint. Defaults to a MemoryCheckpointStore.
+	Store CheckpointStore
+
+	// Handler is called with each event. An error stops Watch; the scan's events are delivered
+	// again once Watch is restarted with the same store.
+	Handler func(ctx context.Context, event UserEvent) error
+
+	// Events receives each event. Watch blocks while the channel is full.
+	Events chan<- UserEvent
+
+	// EmitExisting reports every user as created on the first scan without a checkpoint. By
+	// default the first scan only records a baseline.
+	EmitExisting bool
+
+	// OnError is called when a scan or saving the checkpoint fails. Watch keeps running and
+	// retries on the next tick. Errors are logged when OnError is nil.
+	OnError func(err error)
+}
+
+func (o WatchOptions) validate() error {
+	var errs []error
+
+	if (o.Handler == nil) == (o.Events == nil) {
+		errs = append(errs, NewError(ErrValidation, "exactly one of handler and events is required", nil))
+	}
+	if o.Interval < 0 {
+		errs = append(errs, NewError(ErrValidation, "interval must not be negative", nil))
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+
+	return &MultiError{errors: errs}
+}
+
+// Watch scans users every interval and reports users that were created, updated, deleted or
+// signed in since the previous scan. Changes are detected by comparing Meta.Modified,
+// Meta.LastSignIn and the profile data against the checkpoint, which is saved after the events of
+// a scan have been delivered. Delivery is at least once.
+//
+// Watch blocks until ctx is canceled or the handler fails.
+func (c *userClient) Watch(ctx context.Context, opts WatchOptions) error {
+	if err := opts.validate(); err != nil {
+		return err
+	}
+	if opts.Interval == 0 {
+		opts.Interval = defaultWatchInterval
+	}
+	if opts.Store == nil {
+		opts.Store = NewMemoryCheckpointStore()
+	}
+	if opts.Request.PageSize == nil {
+		opts.Request.PageSize = ToPointer(maxListUsersPageSize)
+	}
+
+	checkpoint, err := opts.Store.Load(ctx)
+	if err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(opts.Interval)
+	defer ticker.Stop()
+
+	for {
+		next, events, err := c.scan(ctx, checkpoint, opts)
+		if err == nil {
+			if err := c.deliver(ctx, events, opts); err != nil {
+				return err
+			}
+			// keep going from the new state even if it could not be persisted
+			checkpoint = next
+			err = opts.Store.Save(ctx, next)
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if opts.OnError != nil {
+				opts.OnError(err)
+			} else {
+				c.logger.Printf("watch failed: %v", err)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// scan pages through the users and returns the new checkpoint with the events since previous.
+func (c *userClient) scan(ctx context.Context, previous *WatchCheckpoint, opts WatchOptions) (*WatchCheckpoint, []UserEvent, error) {
+	now := time.Now()
+	next := &WatchCheckpoint{Version: 1, UpdatedAt: now, Users: map[string]WatchedUser{}}
+
+	var events []UserEvent
+	err := c.eachPage(ctx, opts.Request, func(users []User) error {
+		for i := range users {
+			u := &users[i]
+			id := u.GetID()
+			if id == "" {
+				continue
+			}
+
+			state := WatchedUser{Modified: u.Meta.Modified, LastSignIn: u.Meta.LastSignIn, Data: u.Data}
+			next.Users[id] = state
+
+			if previous == nil {
+				if opts.EmitExisting {
+					events = append(events, UserEvent{Type: UserCreated, UserID: id, User: u, Time: now})
+				}
+				continue
+			}
+
+			old, ok := previous.Users[id]
+			if !ok {
+				events = append(events, UserEvent{Type: UserCreated, UserID: id, User: u, Time: now})
+				continue
+			}
+			if !old.Modified.Equal(state.Modified) || old.Modified.IsZero() {
+				if changes := diffUserData(old.Data, state.Data); len(changes) > 0 {
+					events = append(events, UserEvent{Type: UserUpdated, UserID: id, User: u, Changes: changes, Time: now})
+				}
+			}
+			if state.LastSignIn.After(old.LastSignIn) {
+				events = append(events, UserEvent{Type: UserSignedIn, UserID: id, User: u, Time: now})
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if previous != nil {
+		var deleted []string
+		for id := range previous.Users {
+			if _, ok := next.Users[id]; !ok {
+				deleted = append(deleted, id)
+			}
+		}
+		sort.Strings(deleted)
+		for _, id := range deleted {
+			events = append(events, UserEvent{Type: UserDeleted, UserID: id, Time: now})
+		}
+	}
+
+	return next, events, nil
+}
+
+func (c *userClient) deliver(ctx context.Context, events []UserEvent, opts WatchOptions) error {
+	for _, event := range events {
+		if opts.Handler != nil {
+			if err := opts.Handler(ctx, event); err != nil {
+				return err
+			}
+			continue
+		}
+
+		select {
+		case opts.Events <- event:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	return nil
+}
+
+// diffUserData lists the top-level fields that differ between two copies of profile data.
+func diffUserData(from, to map[string]any) []FieldChange {
+	var changes []FieldChange
+	for field, value := range to {
+		if old, ok := from[field]; !ok || !reflect.DeepEqual(old, value) {
+			changes = append(changes, FieldChange{Field: field, From: old, To: value})
+		}
+	}
+	for field, old := range from {
+		if _, ok := to[field]; !ok {
+			changes = append(changes, FieldChange{Field: field, From: old})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Field < changes[j].Field
+	})
+
+	return changes
+}
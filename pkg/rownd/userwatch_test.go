@@ -0,0 +1,107 @@
+package rownd_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rownd/client-go/pkg/rownd"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUserWatch(t *testing.T) {
+	var (
+		mu    sync.Mutex
+		scans int
+	)
+
+	before := []map[string]any{
+		{"rownd_user": "user_a", "data": map[string]any{"email": "a@example.com"}, "meta": map[string]any{"modified": "2024-01-01T00:00:00Z"}},
+		{"rownd_user": "user_b", "data": map[string]any{"email": "b@example.com"}, "meta": map[string]any{"modified": "2024-01-01T00:00:00Z"}},
+	}
+	after := []map[string]any{
+		{"rownd_user": "user_a", "data": map[string]any{"email": "a@example.com", "plan": "pro"}, "meta": map[string]any{
+			"modified": "2024-01-02T00:00:00Z", "last_sign_in": "2024-01-02T00:00:00Z",
+		}},
+		{"rownd_user": "user_c", "data": map[string]any{"email": "c@example.com"}, "meta": map[string]any{"modified": "2024-01-02T00:00:00Z"}},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/applications/", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		scans++
+		users := after
+		if scans == 1 {
+			users = before
+		}
+		mu.Unlock()
+
+		writeJSON(w, http.StatusOK, map[string]any{"total_results": len(users), "results": users})
+	})
+	client := newTestClient(t, mux)
+
+	store := rownd.NewFileCheckpointStore(filepath.Join(t.TempDir(), "checkpoint.json"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	events := make(chan rownd.UserEvent)
+	done := make(chan error)
+	go func() {
+		done <- client.Users.Watch(ctx, rownd.WatchOptions{
+			Interval: 10 * time.Millisecond,
+			Store:    store,
+			Events:   events,
+		})
+	}()
+
+	var got []rownd.UserEvent
+	for len(got) < 4 {
+		select {
+		case e := <-events:
+			got = append(got, e)
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for events")
+		}
+	}
+	cancel()
+	assert.ErrorIs(t, <-done, context.Canceled)
+
+	assert.Equal(t, rownd.UserUpdated, got[0].Type)
+	assert.Equal(t, "user_a", got[0].UserID)
+	assert.Equal(t, []rownd.FieldChange{{Field: "plan", To: "pro"}}, got[0].Changes)
+	assert.Equal(t, rownd.UserSignedIn, got[1].Type)
+	assert.Equal(t, rownd.UserCreated, got[2].Type)
+	assert.Equal(t, "user_c", got[2].UserID)
+	assert.Equal(t, rownd.UserDeleted, got[3].Type)
+	assert.Equal(t, "user_b", got[3].UserID)
+	assert.Nil(t, got[3].User)
+
+	checkpoint, err := store.Load(context.Background())
+	assert.NoError(t, err)
+	assert.Contains(t, checkpoint.Users, "user_c")
+	assert.NotContains(t, checkpoint.Users, "user_b")
+
+	// restarting from the saved checkpoint reports nothing new
+	boom := errors.New("boom")
+	ctx, cancel = context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	err = client.Users.Watch(ctx, rownd.WatchOptions{
+		Interval: 10 * time.Millisecond,
+		Store:    store,
+		Handler: func(ctx context.Context, e rownd.UserEvent) error {
+			return boom
+		},
+	})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestUserWatchValidation(t *testing.T) {
+	mux := http.NewServeMux()
+	client := newTestClient(t, mux)
+
+	err := client.Users.Watch(context.Background(), rownd.WatchOptions{})
+	assert.Equal(t, rownd.ErrValidation, rownd.KindOf(err))
+}